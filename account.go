@@ -0,0 +1,346 @@
+package tzkt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AccountType is the kind of a tezos account
+type AccountType string
+
+const (
+	AccountTypeUser     AccountType = "user"
+	AccountTypeContract AccountType = "contract"
+	AccountTypeDelegate AccountType = "delegate"
+	// AccountTypeEmpty is an address which never received anything
+	AccountTypeEmpty AccountType = "empty"
+)
+
+// AccountDelegate is the baker an account delegates to
+type AccountDelegate struct {
+	Alias   string `json:"alias"`
+	Address string `json:"address"`
+	Active  bool   `json:"active"`
+}
+
+// AccountInfo is the state of an account. Balances are in mutez, and the
+// fields which do not apply to the type of the account are left empty.
+type AccountInfo struct {
+	Type      AccountType      `json:"type"`
+	ID        uint64           `json:"id"`
+	Address   string           `json:"address"`
+	Alias     string           `json:"alias"`
+	PublicKey string           `json:"publicKey"`
+	Revealed  bool             `json:"revealed"`
+	Balance   int64            `json:"balance"`
+	Counter   int64            `json:"counter"`
+	Delegate  *AccountDelegate `json:"delegate"`
+
+	DelegationLevel uint64    `json:"delegationLevel"`
+	DelegationTime  time.Time `json:"delegationTime"`
+
+	// contracts
+	Kind    string   `json:"kind"`
+	Tzips   []string `json:"tzips"`
+	Creator *Account `json:"creator"`
+
+	// delegates
+	Active         bool  `json:"active"`
+	StakingBalance int64 `json:"stakingBalance"`
+
+	NumContracts        int `json:"numContracts"`
+	NumDelegations      int `json:"numDelegations"`
+	NumOriginations     int `json:"numOriginations"`
+	NumTransactions     int `json:"numTransactions"`
+	NumReveals          int `json:"numReveals"`
+	TokensCount         int `json:"tokensCount"`
+	ActiveTokensCount   int `json:"activeTokensCount"`
+	TokenBalancesCount  int `json:"tokenBalancesCount"`
+	TokenTransfersCount int `json:"tokenTransfersCount"`
+
+	FirstActivity     uint64    `json:"firstActivity"`
+	FirstActivityTime time.Time `json:"firstActivityTime"`
+	LastActivity      uint64    `json:"lastActivity"`
+	LastActivityTime  time.Time `json:"lastActivityTime"`
+
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// AccountFilter selects the accounts returned by GetAccounts. Zero fields do
+// not filter.
+type AccountFilter struct {
+	Type      AccountType
+	Kind      string
+	Delegate  string
+	Addresses []string
+	// MinBalance is the minimum balance in mutez
+	MinBalance int64
+	// ActiveSince is the minimum level of the last activity
+	ActiveSince uint64
+
+	Offset int
+	Limit  int
+}
+
+func (f AccountFilter) query() *Query {
+	q := NewQuery()
+
+	if len(f.Addresses) > 0 {
+		q.In("address", f.Addresses)
+	}
+	if f.Type != "" {
+		q.Set("type", f.Type)
+	}
+	if f.Kind != "" {
+		q.Set("kind", f.Kind)
+	}
+	if f.Delegate != "" {
+		q.Set("delegate", f.Delegate)
+	}
+	if f.MinBalance > 0 {
+		q.Ge("balance", f.MinBalance)
+	}
+	if f.ActiveSince > 0 {
+		q.Ge("lastActivity", f.ActiveSince)
+	}
+
+	return q
+}
+
+// GetAccount returns the account of an address. An address unknown to the
+// chain is an account of type AccountTypeEmpty.
+func (c *TZKT) GetAccount(address string) (AccountInfo, error) {
+	return c.GetAccountWithContext(context.Background(), address)
+}
+
+// GetAccountWithContext is like GetAccount but uses ctx for the api requests
+func (c *TZKT) GetAccountWithContext(ctx context.Context, address string) (AccountInfo, error) {
+	ctx = withMethod(ctx, "GetAccount")
+
+	u := c.apiURL(fmt.Sprintf("/v1/accounts/%s", address), "")
+
+	var account AccountInfo
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return AccountInfo{}, err
+	}
+
+	if err := c.request(req, &account); err != nil {
+		return AccountInfo{}, err
+	}
+
+	if account.Address == "" {
+		return AccountInfo{}, fmt.Errorf("account %w", ErrNotFound)
+	}
+
+	return account, nil
+}
+
+// GetAccounts returns the accounts matching filter, sorted by id
+func (c *TZKT) GetAccounts(filter AccountFilter) ([]AccountInfo, error) {
+	return c.GetAccountsWithContext(context.Background(), filter)
+}
+
+// GetAccountsWithContext is like GetAccounts but uses ctx for the api requests
+func (c *TZKT) GetAccountsWithContext(ctx context.Context, filter AccountFilter) ([]AccountInfo, error) {
+	ctx = withMethod(ctx, "GetAccounts")
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	q := filter.query().
+		SortAsc("id").
+		Offset(filter.Offset).
+		Limit(limit)
+
+	u := c.apiURL("/v1/accounts", q.Encode())
+
+	var accounts []AccountInfo
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.request(req, &accounts); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+// IterateAccounts returns an Iterator over all the accounts matching filter.
+// The Offset and Limit of the filter are ignored.
+func (c *TZKT) IterateAccounts(filter AccountFilter, pageSize int) *Iterator[AccountInfo] {
+	return newListIterator(c, "IterateAccounts", "/v1/accounts", filter.query(), pageSize, func(a AccountInfo) uint64 { return a.ID })
+}
+
+// OperationFilter selects the operations returned by GetAccountOperations.
+// Zero fields do not filter.
+type OperationFilter struct {
+	// Types are the types of the operations, the api defaults to the most
+	// common ones when empty
+	Types []OperationType
+	// FromLevel and ToLevel select the operations in [FromLevel, ToLevel)
+	FromLevel uint64
+	ToLevel   uint64
+	// From and To select the operations in [From, To)
+	From       time.Time
+	To         time.Time
+	Entrypoint string
+	Status     string
+
+	// Ascending returns the oldest operations first instead of the latest
+	Ascending bool
+	// LastID returns the operations after the one with this id, in the order
+	// of the results, to fetch the next page
+	LastID uint64
+	Limit  int
+}
+
+func (f OperationFilter) query() *Query {
+	q := NewQuery()
+
+	if len(f.Types) > 0 {
+		q.Set("type", f.Types)
+	}
+	if f.FromLevel > 0 {
+		q.Ge("level", f.FromLevel)
+	}
+	if f.ToLevel > 0 {
+		q.Lt("level", f.ToLevel)
+	}
+	if !f.From.IsZero() {
+		q.Ge("timestamp", f.From)
+	}
+	if !f.To.IsZero() {
+		q.Lt("timestamp", f.To)
+	}
+	if f.Entrypoint != "" {
+		q.Set("entrypoint", f.Entrypoint)
+	}
+	if f.Status != "" {
+		q.Set("status", f.Status)
+	}
+
+	// the operations of an account are sorted by id with 0 or 1, not by field
+	if f.Ascending {
+		q.Set("sort", 0)
+	} else {
+		q.Set("sort", 1)
+	}
+
+	return q
+}
+
+// GetAccountOperations returns the operations related to an account, latest
+// first unless filter says otherwise. Pass the id of the last operation as
+// filter.LastID to get the next page.
+func (c *TZKT) GetAccountOperations(address string, filter OperationFilter) (Operations, error) {
+	return c.GetAccountOperationsWithContext(context.Background(), address, filter)
+}
+
+// GetAccountOperationsWithContext is like GetAccountOperations but uses ctx for the api requests
+func (c *TZKT) GetAccountOperationsWithContext(ctx context.Context, address string, filter OperationFilter) (Operations, error) {
+	ctx = withMethod(ctx, "GetAccountOperations")
+
+	return c.accountOperations(ctx, address, filter)
+}
+
+func (c *TZKT) accountOperations(ctx context.Context, address string, filter OperationFilter) (Operations, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	q := filter.query().
+		Limit(limit)
+
+	if filter.LastID > 0 {
+		q.Set("lastId", filter.LastID)
+	}
+
+	u := c.apiURL(fmt.Sprintf("/v1/accounts/%s/operations", address), q.Encode())
+
+	var operations Operations
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.request(req, &operations); err != nil {
+		return nil, err
+	}
+
+	return operations, nil
+}
+
+// IterateAccountOperations returns an Iterator over all the operations related
+// to an account matching filter. The LastID and Limit of the filter are ignored.
+func (c *TZKT) IterateAccountOperations(address string, filter OperationFilter, pageSize int) *Iterator[Operation] {
+	return NewIterator(pageSize, func(o Operation) uint64 { return o.Base().ID }, func(ctx context.Context, cursor uint64, limit int) ([]Operation, error) {
+		ctx = withMethod(ctx, "IterateAccountOperations")
+
+		f := filter
+		f.LastID = cursor
+		f.Limit = limit
+
+		return c.accountOperations(ctx, address, f)
+	})
+}
+
+// GetAccountBalanceAtLevel returns the balance in mutez of an account at the
+// end of a block level
+func (c *TZKT) GetAccountBalanceAtLevel(address string, level uint64) (int64, error) {
+	return c.GetAccountBalanceAtLevelWithContext(context.Background(), address, level)
+}
+
+// GetAccountBalanceAtLevelWithContext is like GetAccountBalanceAtLevel but uses ctx for the api requests
+func (c *TZKT) GetAccountBalanceAtLevelWithContext(ctx context.Context, address string, level uint64) (int64, error) {
+	ctx = withMethod(ctx, "GetAccountBalanceAtLevel")
+
+	u := c.apiURL(fmt.Sprintf("/v1/accounts/%s/balance_history/%d", address, level), "")
+
+	var balance int64
+
+	// the balance at a final level never changes, while tzkt answers a level
+	// still to come with the latest balance
+	if c.cache != nil && c.isFinal(ctx, level) {
+		ctx = immutable(ctx)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := c.request(req, &balance); err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+// GetAccountBalanceAtTime returns the balance in mutez of an account at the
+// given time, i.e. at the level of the last block produced before it
+func (c *TZKT) GetAccountBalanceAtTime(address string, at time.Time) (int64, error) {
+	return c.GetAccountBalanceAtTimeWithContext(context.Background(), address, at)
+}
+
+// GetAccountBalanceAtTimeWithContext is like GetAccountBalanceAtTime but uses ctx for the api requests
+func (c *TZKT) GetAccountBalanceAtTimeWithContext(ctx context.Context, address string, at time.Time) (int64, error) {
+	ctx = withMethod(ctx, "GetAccountBalanceAtTime")
+
+	level, err := c.GetLevelByTimeWithContext(ctx, at)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.GetAccountBalanceAtLevelWithContext(ctx, address, level)
+}
@@ -0,0 +1,258 @@
+package tzkt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bitmark-inc/tzkt-go/tzkttest"
+)
+
+const userAccount = `{
+	"id": 1208,
+	"type": "user",
+	"address": "tz1aUser",
+	"alias": "collector",
+	"publicKey": "edpk",
+	"revealed": true,
+	"balance": 1250000,
+	"counter": 42,
+	"delegate": {"alias": "baker", "address": "tz1aBaker", "active": true},
+	"delegationLevel": 100,
+	"delegationTime": "2021-03-01T00:00:00Z",
+	"numContracts": 0,
+	"activeTokensCount": 3,
+	"tokenBalancesCount": 5,
+	"tokenTransfersCount": 9,
+	"numTransactions": 20,
+	"firstActivity": 90,
+	"firstActivityTime": "2021-02-01T00:00:00Z",
+	"lastActivity": 300,
+	"lastActivityTime": "2023-10-01T00:00:00Z",
+	"metadata": {"twitter": "collector"}
+}`
+
+const contractAccount = `{
+	"id": 1300,
+	"type": "contract",
+	"address": "KT1aContract",
+	"kind": "asset",
+	"tzips": ["fa2"],
+	"balance": 0,
+	"creator": {"address": "tz1aUser"},
+	"tokensCount": 10,
+	"lastActivity": 200
+}`
+
+func TestGetAccount(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+	srv.AddAccounts(userAccount, contractAccount)
+
+	c := New("", WithBaseURL(srv.URL))
+
+	account, err := c.GetAccount("tz1aUser")
+	assert.NoError(t, err)
+	assert.Equal(t, AccountTypeUser, account.Type)
+	assert.Equal(t, int64(1250000), account.Balance)
+	assert.Equal(t, int64(42), account.Counter)
+	assert.Equal(t, "tz1aBaker", account.Delegate.Address)
+	assert.True(t, account.Delegate.Active)
+	assert.Equal(t, 3, account.ActiveTokensCount)
+	assert.Equal(t, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), account.FirstActivityTime)
+	assert.Equal(t, uint64(300), account.LastActivity)
+	assert.Equal(t, "collector", account.Metadata["twitter"])
+
+	contract, err := c.GetAccount("KT1aContract")
+	assert.NoError(t, err)
+	assert.Equal(t, AccountTypeContract, contract.Type)
+	assert.Equal(t, "asset", contract.Kind)
+	assert.Equal(t, []string{"fa2"}, contract.Tzips)
+	assert.Equal(t, "tz1aUser", contract.Creator.Address)
+
+	empty, err := c.GetAccount("tz1aNobody")
+	assert.NoError(t, err)
+	assert.Equal(t, AccountTypeEmpty, empty.Type)
+}
+
+func TestGetAccounts(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+	srv.AddAccounts(contractAccount, userAccount)
+
+	c := New("", WithBaseURL(srv.URL))
+
+	accounts, err := c.GetAccounts(AccountFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2)
+	assert.Equal(t, "tz1aUser", accounts[0].Address)
+
+	accounts, err = c.GetAccounts(AccountFilter{Type: AccountTypeContract, Kind: "asset"})
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, "KT1aContract", accounts[0].Address)
+
+	accounts, err = c.GetAccounts(AccountFilter{MinBalance: 1, ActiveSince: 250})
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, "tz1aUser", accounts[0].Address)
+
+	accounts, err = c.GetAccounts(AccountFilter{Addresses: []string{"KT1aContract", "tz1aNobody"}})
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 1)
+
+	it := c.IterateAccounts(AccountFilter{}, 1)
+	var ids []uint64
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []uint64{1208, 1300}, ids)
+}
+
+func TestAccountFilterQuery(t *testing.T) {
+	q := AccountFilter{
+		Type:        AccountTypeDelegate,
+		Delegate:    "tz1aBaker",
+		Addresses:   []string{"tz1a", "tz1b"},
+		MinBalance:  10,
+		ActiveSince: 5,
+	}.query()
+
+	assert.Equal(t, "address.in=tz1a,tz1b&balance.ge=10&delegate=tz1aBaker&lastActivity.ge=5&type=delegate", q.Encode())
+}
+
+func addAccountOperations(srv *tzkttest.Server) {
+	srv.AddAccountOperations("tz1aUser",
+		`{"type":"reveal","id":10,"level":100,"timestamp":"2023-01-01T00:00:00Z","hash":"oo1","status":"applied","sender":{"address":"tz1aUser"},"counter":1,"bakerFee":374}`,
+		`{"type":"delegation","id":11,"level":101,"timestamp":"2023-01-02T00:00:00Z","hash":"oo2","status":"applied","sender":{"address":"tz1aUser"},"newDelegate":{"address":"tz1aBaker"},"amount":1250000}`,
+		`{"type":"transaction","id":12,"level":102,"timestamp":"2023-01-03T00:00:00Z","hash":"oo3","status":"applied","sender":{"address":"tz1aUser"},"target":{"address":"KT1aContract"},"amount":0,"parameter":{"entrypoint":"mint","value":{}}}`,
+		`{"type":"origination","id":13,"level":103,"timestamp":"2023-01-04T00:00:00Z","hash":"oo4","status":"failed","sender":{"address":"tz1aUser"},"originatedContract":{"address":"KT1aNew"},"contractBalance":5}`,
+		`{"type":"endorsement","id":14,"level":104,"timestamp":"2023-01-05T00:00:00Z","hash":"oo5","delegate":{"address":"tz1aUser"},"slots":7}`,
+		`{"type":"transaction","id":15,"level":105,"timestamp":"2023-01-06T00:00:00Z","hash":"oo6","status":"applied","sender":{"address":"tz1aOther"},"target":{"address":"tz1aUser"},"amount":300}`,
+	)
+}
+
+func TestGetAccountOperations(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+	addAccountOperations(srv)
+
+	c := New("", WithBaseURL(srv.URL))
+
+	ops, err := c.GetAccountOperations("tz1aUser", OperationFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, ops, 6)
+
+	// latest first by default
+	assert.Equal(t, uint64(15), ops[0].Base().ID)
+
+	transfer, ok := ops[0].(*TransactionOperation)
+	assert.True(t, ok)
+	assert.Equal(t, int64(300), transfer.Amount)
+	assert.Equal(t, "tz1aOther", transfer.Sender.Address)
+
+	endorsement, ok := ops[1].(*UnknownOperation)
+	assert.True(t, ok)
+	assert.Equal(t, OperationType("endorsement"), endorsement.Type)
+	assert.Contains(t, string(endorsement.Raw), `"slots":7`)
+
+	origination, ok := ops[2].(*OriginationOperation)
+	assert.True(t, ok)
+	assert.Equal(t, "KT1aNew", origination.OriginatedContract.Address)
+	assert.Equal(t, "failed", origination.Status)
+
+	mint, ok := ops[3].(*TransactionOperation)
+	assert.True(t, ok)
+	assert.Equal(t, "mint", mint.Parameter.EntryPoint)
+
+	delegation, ok := ops[4].(*DelegationOperation)
+	assert.True(t, ok)
+	assert.Equal(t, "tz1aBaker", delegation.NewDelegate.Address)
+
+	reveal, ok := ops[5].(*RevealOperation)
+	assert.True(t, ok)
+	assert.Equal(t, int64(374), reveal.BakerFee)
+	assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), reveal.Timestamp)
+}
+
+func TestGetAccountOperationsFilters(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+	addAccountOperations(srv)
+
+	c := New("", WithBaseURL(srv.URL))
+
+	ids := func(ops Operations) []uint64 {
+		var ids []uint64
+		for _, op := range ops {
+			ids = append(ids, op.Base().ID)
+		}
+		return ids
+	}
+
+	ops, err := c.GetAccountOperations("tz1aUser", OperationFilter{
+		Types:     []OperationType{OperationTypeTransaction, OperationTypeOrigination},
+		Ascending: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{12, 13, 15}, ids(ops))
+
+	ops, err = c.GetAccountOperations("tz1aUser", OperationFilter{FromLevel: 101, ToLevel: 103, Ascending: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{11, 12}, ids(ops))
+
+	ops, err = c.GetAccountOperations("tz1aUser", OperationFilter{
+		From: time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{13, 12}, ids(ops))
+
+	ops, err = c.GetAccountOperations("tz1aUser", OperationFilter{Status: "failed"})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{13}, ids(ops))
+
+	// pages of the latest operations
+	ops, err = c.GetAccountOperations("tz1aUser", OperationFilter{Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{15, 14}, ids(ops))
+	ops, err = c.GetAccountOperations("tz1aUser", OperationFilter{Limit: 2, LastID: 14})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{13, 12}, ids(ops))
+
+	it := c.IterateAccountOperations("tz1aUser", OperationFilter{Ascending: true}, 4)
+	var all []uint64
+	for it.Next(context.Background()) {
+		all = append(all, it.Item().Base().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []uint64{10, 11, 12, 13, 14, 15}, all)
+}
+
+func TestOperationFilterQuery(t *testing.T) {
+	q := OperationFilter{
+		Types:      []OperationType{OperationTypeTransaction, OperationTypeDelegation},
+		FromLevel:  10,
+		ToLevel:    20,
+		Entrypoint: "mint",
+		Status:     "applied",
+	}.query()
+
+	assert.Equal(t, "entrypoint=mint&level.ge=10&level.lt=20&sort=1&status=applied&type=transaction,delegation", q.Encode())
+}
+
+func TestGetAccountOperationsEntrypoint(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+	addAccountOperations(srv)
+
+	c := New("", WithBaseURL(srv.URL))
+
+	ops, err := c.GetAccountOperations("tz1aUser", OperationFilter{Entrypoint: "mint"})
+	assert.NoError(t, err)
+	assert.Len(t, ops, 1)
+	assert.Equal(t, "oo3", ops[0].Base().Hash)
+}
@@ -0,0 +1,129 @@
+package tzkt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bitmark-inc/tzkt-go/tzkttest"
+)
+
+func newHistoryServer(t *testing.T) *tzkttest.Server {
+	srv := tzkttest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddBlocks(
+		`{"level":100,"timestamp":"2023-01-01T00:00:00Z"}`,
+		`{"level":200,"timestamp":"2023-02-01T00:00:00Z"}`,
+	)
+	srv.AddAccountBalanceHistory("tz1aUser",
+		`{"level":90,"balance":1000}`,
+		`{"level":150,"balance":2500}`,
+	)
+	srv.AddHistoricalTokenBalances(
+		`{"id":1,"account":{"address":"tz1aUser"},"token":{"contract":{"address":"KT1a"},"tokenId":"1"},"balance":"1","lastLevel":95}`,
+		`{"id":1,"account":{"address":"tz1aUser"},"token":{"contract":{"address":"KT1a"},"tokenId":"1"},"balance":"0","lastLevel":160}`,
+		`{"id":2,"account":{"address":"tz1aUser"},"token":{"contract":{"address":"KT1a"},"tokenId":"2"},"balance":"5","lastLevel":99}`,
+		`{"id":3,"account":{"address":"tz1aOther"},"token":{"contract":{"address":"KT1a"},"tokenId":"1"},"balance":"1","lastLevel":160}`,
+	)
+
+	return srv
+}
+
+func TestGetAccountBalanceAt(t *testing.T) {
+	srv := newHistoryServer(t)
+	c := New("", WithBaseURL(srv.URL))
+
+	balance, err := c.GetAccountBalanceAtLevel("tz1aUser", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), balance)
+
+	balance, err = c.GetAccountBalanceAtLevel("tz1aUser", 80)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), balance)
+
+	balance, err = c.GetAccountBalanceAtTime("tz1aUser", time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2500), balance)
+}
+
+func TestGetTokenBalancesAt(t *testing.T) {
+	srv := newHistoryServer(t)
+	c := New("", WithBaseURL(srv.URL))
+
+	balances, err := c.GetTokenBalancesAtLevel("", "", "tz1aUser", 100)
+	assert.NoError(t, err)
+	assert.Len(t, balances, 2)
+	assert.Equal(t, NullableInt(1), balances[0].Balance)
+	assert.Equal(t, "2", balances[1].Token.ID.String())
+
+	// the first token was transferred away at level 160
+	balances, err = c.GetTokenBalancesAtTime("KT1a", "1", "", time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+	assert.Equal(t, "tz1aOther", balances[0].Account.Address)
+
+	it := c.IterateTokenBalancesAtLevel("KT1a", "", "", 200, 1)
+	var ids []uint64
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []uint64{2, 3}, ids)
+
+	// the balances of every token and owner are not worth reading
+	_, err = c.GetTokenBalancesAtLevel("", "1", "", 100)
+	assert.ErrorIs(t, err, ErrBadRequest)
+
+	it = c.IterateTokenBalancesAtLevel("", "", "", 100, 0)
+	assert.False(t, it.Next(context.Background()))
+	assert.ErrorIs(t, it.Err(), ErrBadRequest)
+}
+
+func TestGetTokenBalancesAtLevelCached(t *testing.T) {
+	srv := newHistoryServer(t)
+	c := New("", WithBaseURL(srv.URL), WithCache(NewLRUCache(10), 0))
+
+	for i := 0; i < 3; i++ {
+		balances, err := c.GetTokenBalancesAtLevel("", "", "tz1aUser", 100)
+		assert.NoError(t, err)
+		assert.Len(t, balances, 2)
+	}
+	// the balances, the head and the latest final level
+	assert.Equal(t, 3, srv.Requests())
+
+	// the head itself is not final yet
+	requests := srv.Requests()
+	for i := 0; i < 2; i++ {
+		_, err := c.GetTokenBalancesAtLevel("", "", "tz1aUser", 200)
+		assert.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, srv.Requests(), requests+2)
+}
+
+func TestGetAccountBalanceAtLevelCached(t *testing.T) {
+	srv := newHistoryServer(t)
+	c := New("", WithBaseURL(srv.URL), WithCache(NewLRUCache(10), 0))
+
+	for i := 0; i < 3; i++ {
+		balance, err := c.GetAccountBalanceAtLevel("tz1aUser", 100)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1000), balance)
+	}
+	// the balance, the head and the latest final level
+	assert.Equal(t, 3, srv.Requests())
+
+	// a level above the head is answered with the latest balance, which
+	// changes once its block arrives
+	balance, err := c.GetAccountBalanceAtLevel("tz1aUser", 300)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2500), balance)
+
+	srv.AddAccountBalanceHistory("tz1aUser", `{"level":300,"balance":4000}`)
+
+	balance, err = c.GetAccountBalanceAtLevel("tz1aUser", 300)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4000), balance)
+}
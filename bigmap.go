@@ -1,11 +1,10 @@
 package tzkt
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
-	"strings"
 	"time"
 )
 
@@ -32,19 +31,22 @@ type BigmapValue struct {
 
 // GetBigMapValueByPointer returns the value of a key in a bigmap.
 func (c *TZKT) GetBigMapValueByPointer(pointer int, key string) ([]byte, error) {
-	u := url.URL{
-		Scheme: "https",
-		Host:   c.endpoint,
-		Path:   fmt.Sprintf("/v1/bigmaps/%d/keys", pointer),
-		RawQuery: url.Values{
-			"select": []string{"value"},
-			"key":    []string{key},
-		}.Encode(),
-	}
+	return c.GetBigMapValueByPointerWithContext(context.Background(), pointer, key)
+}
+
+// GetBigMapValueByPointerWithContext is like GetBigMapValueByPointer but uses ctx for the api requests
+func (c *TZKT) GetBigMapValueByPointerWithContext(ctx context.Context, pointer int, key string) ([]byte, error) {
+	ctx = withMethod(ctx, "GetBigMapValueByPointer")
+
+	q := NewQuery().
+		Select("value").
+		Set("key", key)
+
+	u := c.apiURL(fmt.Sprintf("/v1/bigmaps/%d/keys", pointer), q.Encode())
 
 	var results []json.RawMessage
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +56,7 @@ func (c *TZKT) GetBigMapValueByPointer(pointer int, key string) ([]byte, error)
 	}
 
 	if len(results) == 0 {
-		return nil, fmt.Errorf("error key not found")
+		return nil, fmt.Errorf("error key %w", ErrNotFound)
 	}
 
 	return results[0], nil
@@ -63,25 +65,26 @@ func (c *TZKT) GetBigMapValueByPointer(pointer int, key string) ([]byte, error)
 // GetBigMapPointersByContract returns a list of big map pointer for a contract.
 // This call accepts tags and an option.
 func (c *TZKT) GetBigMapPointersByContract(contract string, tags ...string) ([]int, error) {
-	query := url.Values{
-		"contract": []string{contract},
-		"select":   []string{"ptr"},
-	}
+	return c.GetBigMapPointersByContractWithContext(context.Background(), contract, tags...)
+}
+
+// GetBigMapPointersByContractWithContext is like GetBigMapPointersByContract but uses ctx for the api requests
+func (c *TZKT) GetBigMapPointersByContractWithContext(ctx context.Context, contract string, tags ...string) ([]int, error) {
+	ctx = withMethod(ctx, "GetBigMapPointersByContract")
+
+	q := NewQuery().
+		Set("contract", contract).
+		Select("ptr")
 
 	if len(tags) > 0 {
-		query["tags.any"] = []string{strings.Join(tags, ",")}
+		q.Set("tags.any", tags)
 	}
 
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/bigmaps",
-		RawQuery: query.Encode(),
-	}
+	u := c.apiURL("/v1/bigmaps", q.Encode())
 
 	var pointer []int
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -95,20 +98,23 @@ func (c *TZKT) GetBigMapPointersByContract(contract string, tags ...string) ([]i
 
 // GetBigMapsByContractAndPath get BitMap of contract
 func (c *TZKT) GetBigMapsByContractAndPath(contract string, path string) (int, error) {
-	u := url.URL{
-		Scheme: "https",
-		Host:   c.endpoint,
-		Path:   "/v1/bigmaps",
-		RawQuery: url.Values{
-			"contract": []string{contract},
-			"select":   []string{"ptr"},
-			"path":     []string{path},
-		}.Encode(),
-	}
+	return c.GetBigMapsByContractAndPathWithContext(context.Background(), contract, path)
+}
+
+// GetBigMapsByContractAndPathWithContext is like GetBigMapsByContractAndPath but uses ctx for the api requests
+func (c *TZKT) GetBigMapsByContractAndPathWithContext(ctx context.Context, contract string, path string) (int, error) {
+	ctx = withMethod(ctx, "GetBigMapsByContractAndPath")
+
+	q := NewQuery().
+		Set("contract", contract).
+		Select("ptr").
+		Set("path", path)
+
+	u := c.apiURL("/v1/bigmaps", q.Encode())
 
 	var pointer []int
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return 0, err
 	}
@@ -118,7 +124,7 @@ func (c *TZKT) GetBigMapsByContractAndPath(contract string, path string) (int, e
 	}
 
 	if len(pointer) == 0 {
-		return 0, fmt.Errorf("no pointer")
+		return 0, fmt.Errorf("no pointer: %w", ErrNotFound)
 	}
 
 	return pointer[0], nil
@@ -127,13 +133,20 @@ func (c *TZKT) GetBigMapsByContractAndPath(contract string, path string) (int, e
 // GetBigMapPointerForContractTokenMetadata returns the bigmap pointer of token_metadata
 // for a specific contract
 func (c *TZKT) GetBigMapPointerForContractTokenMetadata(contract string) (int, error) {
-	pointers, err := c.GetBigMapPointersByContract(contract, "token_metadata")
+	return c.GetBigMapPointerForContractTokenMetadataWithContext(context.Background(), contract)
+}
+
+// GetBigMapPointerForContractTokenMetadataWithContext is like GetBigMapPointerForContractTokenMetadata but uses ctx for the api requests
+func (c *TZKT) GetBigMapPointerForContractTokenMetadataWithContext(ctx context.Context, contract string) (int, error) {
+	ctx = withMethod(ctx, "GetBigMapPointerForContractTokenMetadata")
+
+	pointers, err := c.GetBigMapPointersByContractWithContext(ctx, contract, "token_metadata")
 	if err != nil {
 		return 0, err
 	}
 
 	if len(pointers) == 0 {
-		return 0, fmt.Errorf("no pointer")
+		return 0, fmt.Errorf("no pointer: %w", ErrNotFound)
 	}
 
 	return pointers[0], nil
@@ -141,29 +154,18 @@ func (c *TZKT) GetBigMapPointerForContractTokenMetadata(contract string) (int, e
 
 // GetBigmapUpdatesByLevel returns the bigmap updates of given tags
 func (c *TZKT) GetTokenMetadataBigmapUpdatesByLevel(level string, offset, limit int) ([]BigmapUpdate, error) {
-	if limit == 0 {
-		limit = 100
-	}
+	return c.GetTokenMetadataBigmapUpdatesByLevelWithContext(context.Background(), level, offset, limit)
+}
 
-	v := url.Values{
-		"level.eq":  []string{level},
-		"sort":      []string{"level"},
-		"offset":    []string{fmt.Sprint(offset)},
-		"limit":     []string{fmt.Sprint(limit)},
-		"tags.any":  []string{"token_metadata"},
-		"action.ni": []string{"add_key", "allocate"},
-	}
+// GetTokenMetadataBigmapUpdatesByLevelWithContext is like GetTokenMetadataBigmapUpdatesByLevel but uses ctx for the api requests
+func (c *TZKT) GetTokenMetadataBigmapUpdatesByLevelWithContext(ctx context.Context, level string, offset, limit int) ([]BigmapUpdate, error) {
+	ctx = withMethod(ctx, "GetTokenMetadataBigmapUpdatesByLevel")
 
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/bigmaps/updates",
-		RawQuery: v.Encode(),
-	}
+	u := c.apiURL("/v1/bigmaps/updates", tokenMetadataBigmapUpdatesByLevelQuery(level, offset, limit).Encode())
 
 	var bigmaps []BigmapUpdate
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -174,3 +176,49 @@ func (c *TZKT) GetTokenMetadataBigmapUpdatesByLevel(level string, offset, limit
 
 	return bigmaps, nil
 }
+
+// StreamTokenMetadataBigmapUpdatesByLevel is like GetTokenMetadataBigmapUpdatesByLevel
+// but passes the updates to fn one at a time as they are decoded, so that large
+// pages are never held in memory. It stops at the first error returned by fn.
+func (c *TZKT) StreamTokenMetadataBigmapUpdatesByLevel(level string, offset, limit int, fn func(BigmapUpdate) error) error {
+	return c.StreamTokenMetadataBigmapUpdatesByLevelWithContext(context.Background(), level, offset, limit, fn)
+}
+
+// StreamTokenMetadataBigmapUpdatesByLevelWithContext is like StreamTokenMetadataBigmapUpdatesByLevel but uses ctx for the api requests
+func (c *TZKT) StreamTokenMetadataBigmapUpdatesByLevelWithContext(ctx context.Context, level string, offset, limit int, fn func(BigmapUpdate) error) error {
+	ctx = withMethod(ctx, "StreamTokenMetadataBigmapUpdatesByLevel")
+
+	u := c.apiURL("/v1/bigmaps/updates", tokenMetadataBigmapUpdatesByLevelQuery(level, offset, limit).Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	return streamArray(c, req, fn)
+}
+
+func tokenMetadataBigmapUpdatesByLevelQuery(level string, offset, limit int) *Query {
+	if limit == 0 {
+		limit = 100
+	}
+
+	return NewQuery().
+		Eq("level", level).
+		SortAsc("level").
+		Offset(offset).
+		Limit(limit).
+		Set("tags.any", "token_metadata").
+		Ni("action", "add_key", "allocate")
+}
+
+// IterateTokenMetadataBigmapUpdatesByLevel returns an Iterator over all the
+// token_metadata bigmap updates of a block level
+func (c *TZKT) IterateTokenMetadataBigmapUpdatesByLevel(level string, pageSize int) *Iterator[BigmapUpdate] {
+	q := NewQuery().
+		Eq("level", level).
+		Set("tags.any", "token_metadata").
+		Ni("action", "add_key", "allocate")
+
+	return newListIterator(c, "IterateTokenMetadataBigmapUpdatesByLevel", "/v1/bigmaps/updates", q, pageSize, func(b BigmapUpdate) uint64 { return b.ID })
+}
@@ -1,23 +1,345 @@
 package tzkt
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
+	"sort"
 	"time"
 )
 
+// Head is the state of the indexer behind the api, i.e. the last block it has
+// processed
+type Head struct {
+	Chain     string    `json:"chain"`
+	ChainID   string    `json:"chainId"`
+	Cycle     int       `json:"cycle"`
+	Level     uint64    `json:"level"`
+	Hash      string    `json:"hash"`
+	Protocol  string    `json:"protocol"`
+	Timestamp time.Time `json:"timestamp"`
+	// KnownLevel is the level of the chain head known by the indexer, which
+	// is Synced once it has processed it
+	KnownLevel uint64 `json:"knownLevel"`
+	Synced     bool   `json:"synced"`
+}
+
+// Block is a block of the chain. Deposit and Fees are in mutez. The operations
+// of the block are only set when they are requested.
+type Block struct {
+	Cycle     int       `json:"cycle"`
+	Level     uint64    `json:"level"`
+	Hash      string    `json:"hash"`
+	Timestamp time.Time `json:"timestamp"`
+	// Proto is the code of the protocol of the block, whose hash is given by
+	// GetProtocol
+	Proto         int      `json:"proto"`
+	PayloadRound  int      `json:"payloadRound"`
+	BlockRound    int      `json:"blockRound"`
+	Validations   int      `json:"validations"`
+	Deposit       int64    `json:"deposit"`
+	Fees          int64    `json:"fees"`
+	NonceRevealed bool     `json:"nonceRevealed"`
+	Proposer      *Account `json:"proposer"`
+	Producer      *Account `json:"producer"`
+
+	Transactions []TransactionOperation `json:"transactions"`
+	Originations []OriginationOperation `json:"originations"`
+	Delegations  []DelegationOperation  `json:"delegations"`
+	Reveals      []RevealOperation      `json:"reveals"`
+	// OtherOperations are the operations of the types without a field, e.g.
+	// endorsements, ballots or migrations, sorted by id
+	OtherOperations Operations `json:"-"`
+}
+
+// blockOperationFields are the fields of Block holding a type of operations
+var blockOperationFields = map[string]bool{
+	"transactions": true,
+	"originations": true,
+	"delegations":  true,
+	"reveals":      true,
+}
+
+// UnmarshalJSON decodes the lists of operations of the types without a field,
+// e.g. "endorsements", into OtherOperations
+func (b *Block) UnmarshalJSON(data []byte) error {
+	type block Block
+	if err := json.Unmarshal(data, (*block)(b)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	b.OtherOperations = nil
+	for name, value := range fields {
+		// the operations are the only lists of a block
+		if blockOperationFields[name] || !bytes.HasPrefix(bytes.TrimSpace(value), []byte("[")) {
+			continue
+		}
+
+		var operations Operations
+		if err := json.Unmarshal(value, &operations); err != nil {
+			return fmt.Errorf("block %s: %w", name, err)
+		}
+		b.OtherOperations = append(b.OtherOperations, operations...)
+	}
+
+	sort.SliceStable(b.OtherOperations, func(i, j int) bool {
+		return b.OtherOperations[i].Base().ID < b.OtherOperations[j].Base().ID
+	})
+
+	return nil
+}
+
+// Operations returns all the operations of the block, sorted by id
+func (b Block) Operations() Operations {
+	operations := make(Operations, 0, len(b.Transactions)+len(b.Originations)+len(b.Delegations)+len(b.Reveals)+len(b.OtherOperations))
+	for i := range b.Transactions {
+		operations = append(operations, &b.Transactions[i])
+	}
+	for i := range b.Originations {
+		operations = append(operations, &b.Originations[i])
+	}
+	for i := range b.Delegations {
+		operations = append(operations, &b.Delegations[i])
+	}
+	for i := range b.Reveals {
+		operations = append(operations, &b.Reveals[i])
+	}
+	operations = append(operations, b.OtherOperations...)
+
+	sort.SliceStable(operations, func(i, j int) bool { return operations[i].Base().ID < operations[j].Base().ID })
+
+	return operations
+}
+
+// Protocol is a protocol of the chain, as referred to by the Proto code of
+// its blocks
+type Protocol struct {
+	Code       int    `json:"code"`
+	Hash       string `json:"hash"`
+	FirstLevel uint64 `json:"firstLevel"`
+	// LastLevel is zero for the current protocol
+	LastLevel uint64 `json:"lastLevel"`
+}
+
+// BlockFilter selects the blocks returned by GetBlocks. Zero fields do not
+// filter.
+type BlockFilter struct {
+	// FromLevel and ToLevel select the blocks in [FromLevel, ToLevel)
+	FromLevel uint64
+	ToLevel   uint64
+	// From and To select the blocks in [From, To)
+	From     time.Time
+	To       time.Time
+	Proposer string
+
+	Offset int
+	Limit  int
+}
+
+func (f BlockFilter) query() *Query {
+	q := NewQuery()
+
+	if f.FromLevel > 0 {
+		q.Ge("level", f.FromLevel)
+	}
+	if f.ToLevel > 0 {
+		q.Lt("level", f.ToLevel)
+	}
+	if !f.From.IsZero() {
+		q.Ge("timestamp", f.From)
+	}
+	if !f.To.IsZero() {
+		q.Lt("timestamp", f.To)
+	}
+	if f.Proposer != "" {
+		q.Set("proposer", f.Proposer)
+	}
+
+	return q
+}
+
+// GetHead returns the state of the indexer, whose level is the one of the
+// last block available through the api
+func (c *TZKT) GetHead() (Head, error) {
+	return c.GetHeadWithContext(context.Background())
+}
+
+// GetHeadWithContext is like GetHead but uses ctx for the api requests
+func (c *TZKT) GetHeadWithContext(ctx context.Context) (Head, error) {
+	ctx = withMethod(ctx, "GetHead")
+
+	u := c.apiURL("/v1/head", "")
+
+	var head Head
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return Head{}, err
+	}
+
+	if err := c.request(req, &head); err != nil {
+		return Head{}, err
+	}
+
+	return head, nil
+}
+
+// GetProtocol returns the protocol of a code, e.g. the Proto of a block
+func (c *TZKT) GetProtocol(code int) (Protocol, error) {
+	return c.GetProtocolWithContext(context.Background(), code)
+}
+
+// GetProtocolWithContext is like GetProtocol but uses ctx for the api requests
+func (c *TZKT) GetProtocolWithContext(ctx context.Context, code int) (Protocol, error) {
+	ctx = withMethod(ctx, "GetProtocol")
+
+	u := c.apiURL(fmt.Sprintf("/v1/protocols/%d", code), "")
+
+	var protocol Protocol
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return Protocol{}, err
+	}
+
+	if err := c.request(req, &protocol); err != nil {
+		return Protocol{}, err
+	}
+
+	return protocol, nil
+}
+
+// GetBlock returns the block of a level or a hash, along with its operations
+// when operations is true
+func (c *TZKT) GetBlock(levelOrHash string, operations bool) (Block, error) {
+	return c.GetBlockWithContext(context.Background(), levelOrHash, operations)
+}
+
+// GetBlockWithContext is like GetBlock but uses ctx for the api requests
+func (c *TZKT) GetBlockWithContext(ctx context.Context, levelOrHash string, operations bool) (Block, error) {
+	ctx = withMethod(ctx, "GetBlock")
+
+	q := NewQuery()
+	if operations {
+		q.Set("operations", true)
+	}
+
+	u := c.apiURL(fmt.Sprintf("/v1/blocks/%s", levelOrHash), q.Encode())
+
+	var block Block
+
+	// a recent block may still be reorganized, even when asked by hash
+	req, err := http.NewRequestWithContext(immutableIf(ctx, c.finalLevels), "GET", u.String(), nil)
+	if err != nil {
+		return Block{}, err
+	}
+
+	if err := c.request(req, &block); err != nil {
+		return Block{}, err
+	}
+
+	if block.Hash == "" {
+		return Block{}, fmt.Errorf("block %w", ErrNotFound)
+	}
+
+	return block, nil
+}
+
+// GetBlocks returns the blocks matching filter, sorted by level. The operations
+// of the blocks are not included.
+func (c *TZKT) GetBlocks(filter BlockFilter) ([]Block, error) {
+	return c.GetBlocksWithContext(context.Background(), filter)
+}
+
+// GetBlocksWithContext is like GetBlocks but uses ctx for the api requests
+func (c *TZKT) GetBlocksWithContext(ctx context.Context, filter BlockFilter) ([]Block, error) {
+	ctx = withMethod(ctx, "GetBlocks")
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	q := filter.query().
+		SortAsc("level").
+		Offset(filter.Offset).
+		Limit(limit)
+
+	return c.blocks(ctx, q)
+}
+
+// IterateBlocks returns an Iterator over all the blocks matching filter. The
+// Offset and Limit of the filter are ignored.
+func (c *TZKT) IterateBlocks(filter BlockFilter, pageSize int) *Iterator[Block] {
+	// blocks have no id, the level is used as the cursor. It is zero for the
+	// genesis block too, so the first page is tracked on its own.
+	started := false
+
+	return NewIterator(pageSize, func(b Block) uint64 { return b.Level }, func(ctx context.Context, cursor uint64, limit int) ([]Block, error) {
+		ctx = withMethod(ctx, "IterateBlocks")
+
+		q := filter.query().
+			SortAsc("level").
+			Limit(limit)
+
+		if started {
+			q.Gt("level", cursor)
+		}
+
+		blocks, err := c.blocks(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		started = true
+
+		return blocks, nil
+	})
+}
+
+func (c *TZKT) blocks(ctx context.Context, q *Query) ([]Block, error) {
+	u := c.apiURL("/v1/blocks", q.Encode())
+
+	var blocks []Block
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.request(req, &blocks); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
 // GetLevelByTime returns the block level of the given time
 func (c *TZKT) GetLevelByTime(at time.Time) (uint64, error) {
-	u := url.URL{
-		Scheme: "https",
-		Host:   c.endpoint,
-		Path:   fmt.Sprintf("/v1/blocks/%s/level", at.UTC().Format(time.RFC3339)),
-	}
+	return c.GetLevelByTimeWithContext(context.Background(), at)
+}
+
+// GetLevelByTimeWithContext is like GetLevelByTime but uses ctx for the api requests
+func (c *TZKT) GetLevelByTimeWithContext(ctx context.Context, at time.Time) (uint64, error) {
+	ctx = withMethod(ctx, "GetLevelByTime")
+
+	u := c.apiURL(fmt.Sprintf("/v1/blocks/%s/level", at.UTC().Format(time.RFC3339)), "")
 
 	var level uint64
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	// the level of a time is only known for sure once its block is final
+	final := c.cache != nil && c.isFinalTime(ctx, at)
+	if final {
+		ctx = immutable(ctx)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return 0, err
 	}
@@ -25,5 +347,119 @@ func (c *TZKT) GetLevelByTime(at time.Time) (uint64, error) {
 		return 0, err
 	}
 
+	if final {
+		c.setFinalLevel(level)
+	}
+
+	return level, nil
+}
+
+// GetTimeByLevel returns the time of the block of a level. The times of final
+// blocks are memoized by the client.
+func (c *TZKT) GetTimeByLevel(level uint64) (time.Time, error) {
+	return c.GetTimeByLevelWithContext(context.Background(), level)
+}
+
+// GetTimeByLevelWithContext is like GetTimeByLevel but uses ctx for the api requests
+func (c *TZKT) GetTimeByLevelWithContext(ctx context.Context, level uint64) (time.Time, error) {
+	ctx = withMethod(ctx, "GetTimeByLevel")
+
+	if at, ok := c.levels.getTime(level); ok {
+		return at, nil
+	}
+
+	u := c.apiURL(fmt.Sprintf("/v1/blocks/%d/timestamp", level), "")
+
+	var timestamp time.Time
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := c.request(req, &timestamp); err != nil {
+		return time.Time{}, err
+	}
+
+	if timestamp.IsZero() {
+		return time.Time{}, fmt.Errorf("block %w", ErrNotFound)
+	}
+
+	// the time of a level is only known for sure once its block is final
+	if c.isFinalTime(ctx, timestamp) {
+		c.levels.setTime(level, timestamp)
+		c.setFinalLevel(level)
+	}
+
+	return timestamp, nil
+}
+
+// LevelRange is the range [From, To) of the levels of the blocks produced in
+// a time window
+type LevelRange struct {
+	From uint64
+	To   uint64
+}
+
+// Len returns the number of levels in the range
+func (r LevelRange) Len() uint64 {
+	if r.To <= r.From {
+		return 0
+	}
+
+	return r.To - r.From
+}
+
+// Contains reports whether level is in the range
+func (r LevelRange) Contains(level uint64) bool {
+	return level >= r.From && level < r.To
+}
+
+// GetLevelRange returns the range of the levels of the blocks produced in
+// [from, to). The levels of past times are memoized by the client, so ranges
+// of consecutive windows, e.g. days, cost a single lookup each.
+func (c *TZKT) GetLevelRange(from, to time.Time) (LevelRange, error) {
+	return c.GetLevelRangeWithContext(context.Background(), from, to)
+}
+
+// GetLevelRangeWithContext is like GetLevelRange but uses ctx for the api requests
+func (c *TZKT) GetLevelRangeWithContext(ctx context.Context, from, to time.Time) (LevelRange, error) {
+	ctx = withMethod(ctx, "GetLevelRange")
+
+	start, err := c.levelBefore(ctx, from)
+	if err != nil {
+		return LevelRange{}, err
+	}
+
+	end, err := c.levelBefore(ctx, to)
+	if err != nil {
+		return LevelRange{}, err
+	}
+
+	return LevelRange{From: start + 1, To: end + 1}, nil
+}
+
+// levelBefore returns the level of the last block produced before at,
+// memoized once the block is final
+func (c *TZKT) levelBefore(ctx context.Context, at time.Time) (uint64, error) {
+	// blocks are timestamped in seconds, so the last one before at is the
+	// last one at or before the previous second
+	before := at.Truncate(time.Second)
+	if before.Equal(at) {
+		before = before.Add(-time.Second)
+	}
+
+	if level, ok := c.levels.get(before); ok {
+		return level, nil
+	}
+
+	level, err := c.GetLevelByTimeWithContext(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.isFinalTime(ctx, before) {
+		c.levels.set(before, level)
+	}
+
 	return level, nil
 }
@@ -0,0 +1,262 @@
+package tzkt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bitmark-inc/tzkt-go/tzkttest"
+)
+
+func newBlockServer(t *testing.T) *tzkttest.Server {
+	srv := tzkttest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddBlocks(
+		`{"cycle":699,"level":99,"hash":"BL0","timestamp":"2022-12-31T23:59:52Z","proto":18,"payloadRound":0,"fees":0,"proposer":{"address":"tz1baker2"}}`,
+		`{"cycle":700,"level":100,"hash":"BLa","timestamp":"2023-01-01T00:00:00Z","proto":18,"payloadRound":0,"fees":1500,"proposer":{"address":"tz1baker1"}}`,
+		`{"cycle":700,"level":101,"hash":"BLb","timestamp":"2023-01-01T00:00:08Z","proto":18,"payloadRound":1,"fees":2400,"proposer":{"address":"tz1baker2"},
+			"transactions":[{"type":"transaction","id":12,"level":101,"hash":"oo2","bakerFee":900,"target":{"address":"KT1a"},"amount":5}],
+			"reveals":[{"type":"reveal","id":11,"level":101,"hash":"oo1","bakerFee":1500}],
+			"endorsements":[{"type":"endorsement","id":10,"level":101,"hash":"oo0","slots":7}],
+			"migrations":[{"type":"migration","id":13,"level":101,"kind":"subsidy","balanceChange":2500}]}`,
+		`{"cycle":701,"level":102,"hash":"BLc","timestamp":"2023-01-01T00:00:16Z","proto":18,"payloadRound":0,"fees":0,"proposer":{"address":"tz1baker1"}}`,
+	)
+
+	return srv
+}
+
+func TestGetHead(t *testing.T) {
+	srv := newBlockServer(t)
+	c := New("", WithBaseURL(srv.URL))
+
+	head, err := c.GetHead()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(102), head.Level)
+	assert.Equal(t, "BLc", head.Hash)
+	assert.Equal(t, 701, head.Cycle)
+	assert.True(t, head.Synced)
+}
+
+func TestGetBlock(t *testing.T) {
+	srv := newBlockServer(t)
+	c := New("", WithBaseURL(srv.URL))
+
+	block, err := c.GetBlock("101", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "BLb", block.Hash)
+	assert.Equal(t, 1, block.PayloadRound)
+	assert.Equal(t, int64(2400), block.Fees)
+	assert.Equal(t, 18, block.Proto)
+	assert.Equal(t, "tz1baker2", block.Proposer.Address)
+	assert.Empty(t, block.Operations())
+	assert.Nil(t, block.OtherOperations)
+
+	block, err = c.GetBlock("BLb", true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(101), block.Level)
+	assert.Len(t, block.Transactions, 1)
+	assert.Equal(t, int64(5), block.Transactions[0].Amount)
+
+	// the operations without a field are kept as they are
+	assert.Len(t, block.OtherOperations, 2)
+	assert.Equal(t, OperationType("endorsement"), block.OtherOperations[0].Base().Type)
+	assert.JSONEq(t, `{"type":"migration","id":13,"level":101,"kind":"subsidy","balanceChange":2500}`, string(block.OtherOperations[1].(*UnknownOperation).Raw))
+
+	operations := block.Operations()
+	assert.Len(t, operations, 4)
+	assert.IsType(t, &UnknownOperation{}, operations[0])
+	assert.IsType(t, &RevealOperation{}, operations[1])
+	assert.IsType(t, &TransactionOperation{}, operations[2])
+	assert.IsType(t, &UnknownOperation{}, operations[3])
+
+	_, err = c.GetBlock("200", false)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGetProtocol(t *testing.T) {
+	srv := newBlockServer(t)
+	srv.AddProtocols(`{"code":18,"hash":"ProxfordYmVfjWnRcgjWH36fW6PArwqykTFzotUxRs6gmTcZDuH","firstLevel":5070849}`)
+	c := New("", WithBaseURL(srv.URL))
+
+	block, err := c.GetBlock("100", false)
+	assert.NoError(t, err)
+
+	protocol, err := c.GetProtocol(block.Proto)
+	assert.NoError(t, err)
+	assert.Equal(t, "ProxfordYmVfjWnRcgjWH36fW6PArwqykTFzotUxRs6gmTcZDuH", protocol.Hash)
+	assert.Equal(t, uint64(5070849), protocol.FirstLevel)
+	assert.Zero(t, protocol.LastLevel)
+
+	_, err = c.GetProtocol(19)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGetBlockCachesFinalBlocks(t *testing.T) {
+	srv := newFinalBlockServer(t)
+	c := New("", WithBaseURL(srv.URL), WithCache(NewLRUCache(10), 0))
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetBlock("BLa", false)
+		assert.NoError(t, err)
+		_, err = c.GetBlock("100", false)
+		assert.NoError(t, err)
+	}
+	// the blocks, the head and the latest final level
+	assert.Equal(t, 4, srv.Requests())
+
+	// recent blocks may be reorganized, even by hash
+	srv = newBlockServer(t)
+	c = New("", WithBaseURL(srv.URL), WithCache(NewLRUCache(10), 0))
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetBlock("BLa", false)
+		assert.NoError(t, err)
+	}
+	requests := srv.Requests()
+	_, err := c.GetBlock("BLa", false)
+	assert.NoError(t, err)
+	assert.Less(t, requests, srv.Requests())
+}
+
+func TestGetBlocks(t *testing.T) {
+	srv := newBlockServer(t)
+	c := New("", WithBaseURL(srv.URL))
+
+	blocks, err := c.GetBlocks(BlockFilter{FromLevel: 100, ToLevel: 102})
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 2)
+	assert.Equal(t, uint64(100), blocks[0].Level)
+	assert.Nil(t, blocks[1].Transactions)
+
+	blocks, err = c.GetBlocks(BlockFilter{From: time.Date(2023, 1, 1, 0, 0, 1, 0, time.UTC), Proposer: "tz1baker1"})
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 1)
+	assert.Equal(t, "BLc", blocks[0].Hash)
+
+	var levels []uint64
+	it := c.IterateBlocks(BlockFilter{FromLevel: 100}, 2)
+	for it.Next(context.Background()) {
+		levels = append(levels, it.Item().Level)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []uint64{100, 101, 102}, levels)
+}
+
+// newFinalBlockServer serves the blocks of newBlockServer as final, behind a
+// head a day later
+func newFinalBlockServer(t *testing.T) *tzkttest.Server {
+	srv := newBlockServer(t)
+	srv.AddBlocks(`{"cycle":710,"level":10900,"hash":"BLz","timestamp":"2023-01-02T00:00:00Z","proto":18}`)
+
+	return srv
+}
+
+func TestGetTimeByLevel(t *testing.T) {
+	srv := newFinalBlockServer(t)
+	c := New("", WithBaseURL(srv.URL))
+
+	at, err := c.GetTimeByLevel(101)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 8, 0, time.UTC), at)
+
+	_, err = c.GetTimeByLevel(200)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// the times of past levels are memoized
+	requests := srv.Requests()
+	for i := 0; i < 3; i++ {
+		at, err = c.GetTimeByLevel(101)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 8, 0, time.UTC), at)
+	}
+	assert.Equal(t, requests, srv.Requests())
+}
+
+func TestGetLevelRange(t *testing.T) {
+	srv := newFinalBlockServer(t)
+	c := New("", WithBaseURL(srv.URL))
+
+	day := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r, err := c.GetLevelRange(day, day.Add(16*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, LevelRange{From: 100, To: 102}, r)
+	assert.Equal(t, uint64(2), r.Len())
+	assert.True(t, r.Contains(101))
+	assert.False(t, r.Contains(102))
+	// the levels and the head telling they are final
+	assert.Equal(t, 3, srv.Requests())
+
+	// a block at the start of the window is in the range
+	r, err = c.GetLevelRange(day.Add(8*time.Second), day.Add(8500*time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, LevelRange{From: 101, To: 102}, r)
+
+	// the levels of past times are memoized
+	requests := srv.Requests()
+	r, err = c.GetLevelRange(day.Add(16*time.Second), day.Add(24*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, LevelRange{From: 102, To: 103}, r)
+	assert.Equal(t, requests+1, srv.Requests())
+}
+
+func TestIterateBlocksFromGenesis(t *testing.T) {
+	srv := tzkttest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddBlocks(
+		`{"level":0,"hash":"BLgenesis","timestamp":"2018-06-30T16:07:32Z"}`,
+		`{"level":1,"hash":"BLfirst","timestamp":"2018-06-30T17:39:57Z"}`,
+	)
+	c := New("", WithBaseURL(srv.URL))
+
+	var levels []uint64
+	it := c.IterateBlocks(BlockFilter{}, 1)
+	for it.Next(context.Background()) && len(levels) < 5 {
+		levels = append(levels, it.Item().Level)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []uint64{0, 1}, levels)
+}
+
+func TestGetTimeByLevelNotFinal(t *testing.T) {
+	srv := newBlockServer(t)
+	c := New("", WithBaseURL(srv.URL))
+
+	// the head is only seconds after the block, which may still be reorganized
+	for i := 0; i < 2; i++ {
+		at, err := c.GetTimeByLevel(101)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 8, 0, time.UTC), at)
+	}
+	assert.Equal(t, 4, srv.Requests())
+
+	// nor is anything final while the indexer is not synced
+	srv = newFinalBlockServer(t)
+	srv.SetSynced(false)
+	c = New("", WithBaseURL(srv.URL))
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetLevelRange(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 16, 0, time.UTC))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 8, srv.Requests())
+}
+
+func TestGetTimeByLevelRecent(t *testing.T) {
+	srv := tzkttest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddBlocks(map[string]interface{}{"level": 500, "hash": "BLnow", "timestamp": time.Now().UTC().Format(time.RFC3339)})
+	c := New("", WithBaseURL(srv.URL))
+
+	// a recent block may still be reorganized
+	for i := 0; i < 2; i++ {
+		_, err := c.GetTimeByLevel(500)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, srv.Requests())
+}
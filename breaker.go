@@ -0,0 +1,148 @@
+package tzkt
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the circuit breaker of a client
+type CircuitState int
+
+const (
+	// CircuitClosed lets the requests through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails the requests with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets a single trial request through to decide whether
+	// the api is back
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// WithCircuitBreaker stops calling the api after maxFailures consecutive
+// failed requests, failing fast with ErrCircuitOpen instead. Once coolDown has
+// passed, a single request is let through and closes the circuit if it
+// succeeds. Network errors and 5xx responses count as failures.
+func WithCircuitBreaker(maxFailures int, coolDown time.Duration) Option {
+	return func(c *TZKT) {
+		c.breaker = newCircuitBreaker(maxFailures, coolDown)
+	}
+}
+
+// CircuitState returns the state of the circuit breaker of the client, for
+// health checks. It is always CircuitClosed without a circuit breaker.
+func (c *TZKT) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+
+	return c.breaker.current()
+}
+
+type circuitBreaker struct {
+	sync.Mutex
+
+	maxFailures int
+	coolDown    time.Duration
+
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func newCircuitBreaker(maxFailures int, coolDown time.Duration) *circuitBreaker {
+	if maxFailures < 1 {
+		maxFailures = 1
+	}
+
+	return &circuitBreaker{
+		maxFailures: maxFailures,
+		coolDown:    coolDown,
+	}
+}
+
+// current returns the state of the breaker, half-open once the cool-down of an
+// open breaker has passed
+func (b *circuitBreaker) current() CircuitState {
+	b.Lock()
+	defer b.Unlock()
+
+	b.update()
+
+	return b.state
+}
+
+func (b *circuitBreaker) update() {
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.coolDown {
+		b.state = CircuitHalfOpen
+		b.trial = false
+	}
+}
+
+// allow reports whether a request can be sent. Every allowed request must be
+// followed by a call to done.
+func (b *circuitBreaker) allow() error {
+	b.Lock()
+	defer b.Unlock()
+
+	b.update()
+
+	switch b.state {
+	case CircuitOpen:
+		return ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.trial = true
+	}
+
+	return nil
+}
+
+// done records the outcome of an allowed request. It returns true when the
+// request opened the circuit.
+func (b *circuitBreaker) done(failed bool) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	if !failed {
+		b.state = CircuitClosed
+		b.failures = 0
+		b.trial = false
+		return false
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.maxFailures {
+		opened := b.state != CircuitOpen
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+		b.trial = false
+		return opened
+	}
+
+	return false
+}
+
+// cancel releases the trial of a request whose outcome says nothing about the
+// api, e.g. because the caller gave up
+func (b *circuitBreaker) cancel() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.trial = false
+}
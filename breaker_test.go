@@ -0,0 +1,124 @@
+package tzkt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var calls, status int32
+	atomic.StoreInt32(&status, http.StatusServiceUnavailable)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if s := atomic.LoadInt32(&status); s != http.StatusOK {
+			w.WriteHeader(int(s))
+			return
+		}
+		fmt.Fprint(w, "1")
+	}))
+	defer ts.Close()
+
+	c := New("", WithBaseURL(ts.URL), WithCircuitBreaker(2, 50*time.Millisecond))
+	assert.Equal(t, CircuitClosed, c.CircuitState())
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetTokenTransfersCount("KT1", "1")
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+	assert.Equal(t, CircuitOpen, c.CircuitState())
+
+	// fails fast without calling the api
+	_, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// a failed trial opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, CircuitHalfOpen, c.CircuitState())
+	_, err = c.GetTokenTransfersCount("KT1", "1")
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, CircuitOpen, c.CircuitState())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// a successful trial closes it
+	atomic.StoreInt32(&status, http.StatusOK)
+	time.Sleep(60 * time.Millisecond)
+	count, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, CircuitClosed, c.CircuitState())
+}
+
+func TestCircuitBreakerClientErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	c := New("", WithBaseURL(ts.URL), WithCircuitBreaker(1, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		_, err := c.GetTokenTransfersCount("KT1", "1")
+		assert.ErrorIs(t, err, ErrBadRequest)
+	}
+	assert.Equal(t, CircuitClosed, c.CircuitState())
+}
+
+func TestCircuitBreakerStopsRetries(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	c := New("",
+		WithBaseURL(ts.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		WithCircuitBreaker(2, time.Minute),
+	)
+
+	_, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCircuitBreakerHalfOpenTrial(t *testing.T) {
+	b := newCircuitBreaker(1, 0)
+	assert.NoError(t, b.allow())
+	assert.True(t, b.done(true))
+
+	// only one request is let through while half-open
+	assert.Equal(t, CircuitHalfOpen, b.current())
+	assert.NoError(t, b.allow())
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	// a canceled trial lets another one through
+	b.cancel()
+	assert.NoError(t, b.allow())
+	assert.False(t, b.done(false))
+	assert.Equal(t, CircuitClosed, b.current())
+}
+
+func TestCircuitBreakerCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	c := New("", WithBaseURL(ts.URL), WithCircuitBreaker(1, time.Minute))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GetTokenTransfersCountWithContext(ctx, "KT1", "1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, CircuitClosed, c.CircuitState())
+}
@@ -0,0 +1,152 @@
+package tzkt
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Cache stores the bodies of successful api responses by request url
+type Cache interface {
+	// Get returns the value of a key which is not expired
+	Get(key string) ([]byte, bool)
+	// Set stores a value for ttl, or indefinitely when ttl is zero
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// WithCache caches the api responses in cache. Data which can not change
+// anymore, such as the operations and blocks of final levels or the level of a
+// final time, is cached indefinitely. Other data, such as balances and owners,
+// is cached for ttl, or not cached at all when ttl is zero.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *TZKT) {
+		c.cache = cache
+		c.mutableTTL = ttl
+	}
+}
+
+type immutableKey struct{}
+
+// immutable marks the requests made with the returned context as returning
+// data which never changes once it exists
+func immutable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, immutableKey{}, true)
+}
+
+// immutableCheck reports whether a response body never changes
+type immutableCheck func(ctx context.Context, body []byte) bool
+
+// immutableIf marks the requests made with the returned context as returning
+// data which never changes once check reports so for their response body
+func immutableIf(ctx context.Context, check immutableCheck) context.Context {
+	return context.WithValue(ctx, immutableKey{}, check)
+}
+
+// emptyBodies are responses for data which does not exist (yet)
+var emptyBodies = [][]byte{nil, []byte("[]"), []byte("null")}
+
+// cacheTTL returns how long a response body can be cached for
+func (c *TZKT) cacheTTL(ctx context.Context, body []byte) (time.Duration, bool) {
+	if mark := ctx.Value(immutableKey{}); mark != nil {
+		// missing data may show up later, so it is not kept forever
+		isEmpty := false
+		for _, e := range emptyBodies {
+			if bytes.Equal(bytes.TrimSpace(body), e) {
+				isEmpty = true
+				break
+			}
+		}
+
+		if !isEmpty {
+			check, ok := mark.(immutableCheck)
+			// the requests made by check are not marked
+			if !ok || check(context.WithValue(ctx, immutableKey{}, nil), body) {
+				return 0, true
+			}
+		}
+	}
+
+	return c.mutableTTL, c.mutableTTL > 0
+}
+
+// LRUCache is an in-memory Cache which evicts the least recently used entries
+// beyond its size. It is safe for concurrent use.
+type LRUCache struct {
+	sync.Mutex
+
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache returns an LRUCache holding up to size entries
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (l *LRUCache) Get(key string) ([]byte, bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	e, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := e.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		l.remove(e)
+		return nil, false
+	}
+
+	l.order.MoveToFront(e)
+
+	return entry.value, true
+}
+
+func (l *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	l.Lock()
+	defer l.Unlock()
+
+	entry := &lruEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+
+	if e, ok := l.entries[key]; ok {
+		e.Value = entry
+		l.order.MoveToFront(e)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(entry)
+
+	for l.size > 0 && l.order.Len() > l.size {
+		l.remove(l.order.Back())
+	}
+}
+
+// Len returns the number of entries, including the expired ones not evicted yet
+func (l *LRUCache) Len() int {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.order.Len()
+}
+
+func (l *LRUCache) remove(e *list.Element) {
+	l.order.Remove(e)
+	delete(l.entries, e.Value.(*lruEntry).key)
+}
@@ -0,0 +1,189 @@
+package tzkt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bitmark-inc/tzkt-go/tzkttest"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Set("a", []byte("1"), 0)
+	c.Set("b", []byte("2"), 0)
+
+	// a becomes the most recently used
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), v)
+
+	c.Set("c", []byte("3"), 0)
+	assert.Equal(t, 2, c.Len())
+
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	c := NewLRUCache(10)
+
+	c.Set("a", []byte("1"), 20*time.Millisecond)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	time.Sleep(30 * time.Millisecond)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func newCountingServer(t *testing.T, body string) (*httptest.Server, *int32) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, &calls
+}
+
+func TestCacheImmutable(t *testing.T) {
+	srv := tzkttest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddBlocks(
+		`{"level":100,"timestamp":"2023-01-01T00:00:00Z"}`,
+		`{"level":10900,"timestamp":"2023-01-02T00:00:00Z"}`,
+	)
+	srv.AddTransactions(
+		`{"id":1,"level":100,"hash":"oo1","status":"applied"}`,
+		`{"id":2,"level":10900,"hash":"oo2","status":"applied"}`,
+	)
+	c := New("", WithBaseURL(srv.URL), WithCache(NewLRUCache(10), 0))
+
+	// the transaction, the head and the latest final level
+	for i := 0; i < 3; i++ {
+		tx, err := c.GetTransaction(1)
+		assert.NoError(t, err)
+		assert.Equal(t, "oo1", tx.Hash)
+	}
+	assert.Equal(t, 3, srv.Requests())
+
+	// the status and the transactions telling their block is final
+	for i := 0; i < 2; i++ {
+		status, err := c.GetTransactionStatusByTx("oo1")
+		assert.NoError(t, err)
+		assert.True(t, *status)
+	}
+	assert.Equal(t, 5, srv.Requests())
+
+	// a transaction of a recent block may still be reorganized
+	requests := srv.Requests()
+	for i := 0; i < 2; i++ {
+		tx, err := c.GetTransaction(2)
+		assert.NoError(t, err)
+		assert.Equal(t, "oo2", tx.Hash)
+	}
+	assert.GreaterOrEqual(t, srv.Requests(), requests+2)
+
+	// mutable data is not cached without a ttl
+	requests = srv.Requests()
+	for i := 0; i < 2; i++ {
+		_, err := c.GetTransactions(nil, nil, nil, 0, 10)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, requests+2, srv.Requests())
+}
+
+func TestCacheImmutableEmpty(t *testing.T) {
+	ts, calls := newCountingServer(t, `[]`)
+	c := New("", WithBaseURL(ts.URL), WithCache(NewLRUCache(10), 0))
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetTransactionByTx("oo1")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestCacheTTL(t *testing.T) {
+	ts, calls := newCountingServer(t, `7`)
+	c := New("", WithBaseURL(ts.URL), WithCache(NewLRUCache(10), 50*time.Millisecond))
+
+	for i := 0; i < 2; i++ {
+		count, err := c.GetTokenTransfersCount("KT1", "1")
+		assert.NoError(t, err)
+		assert.Equal(t, 7, count)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	time.Sleep(60 * time.Millisecond)
+	_, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestCacheLevelByTime(t *testing.T) {
+	srv := tzkttest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddBlocks(
+		`{"level":100,"timestamp":"2023-09-30T00:00:00Z"}`,
+		`{"level":200,"timestamp":"2023-10-02T00:00:00Z"}`,
+	)
+	c := New("", WithBaseURL(srv.URL), WithCache(NewLRUCache(10), 0))
+
+	// the level and the head telling it is final
+	past := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		level, err := c.GetLevelByTime(past)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(100), level)
+	}
+	assert.Equal(t, 2, srv.Requests())
+
+	// the head is before the time, whose level is still to come
+	later := time.Date(2023, 10, 3, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		level, err := c.GetLevelByTime(later)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(200), level)
+	}
+	assert.Equal(t, 6, srv.Requests())
+
+	// a recent block may still be reorganized
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		_, err := c.GetLevelByTime(now)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 8, srv.Requests())
+}
+
+func TestCacheLevelByTimeUnsynced(t *testing.T) {
+	srv := tzkttest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddBlocks(
+		`{"level":100,"timestamp":"2023-09-30T00:00:00Z"}`,
+		`{"level":200,"timestamp":"2023-10-02T00:00:00Z"}`,
+	)
+	srv.SetSynced(false)
+	c := New("", WithBaseURL(srv.URL), WithCache(NewLRUCache(10), 0))
+
+	past := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		_, err := c.GetLevelByTime(past)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 4, srv.Requests())
+}
@@ -2,57 +2,371 @@ package tzkt
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
-var ErrTooManyRequest = fmt.Errorf("too many requests")
+// networkEndpoints maps the known network names to the hosts of the public TzKT API
+var networkEndpoints = map[string]string{
+	"mainnet":   "api.mainnet.tzkt.io",
+	"testnet":   "api.ghostnet.tzkt.io",
+	"ghostnet":  "api.ghostnet.tzkt.io",
+	"oxfordnet": "api.oxfordnet.tzkt.io",
+	"parisnet":  "api.parisnet.tzkt.io",
+}
 
 type TZKT struct {
+	scheme   string
 	endpoint string
+	basePath string
+
+	userAgent string
+
+	retry      RetryPolicy
+	limiter    *rateLimiter
+	endpoints  *endpointPool
+	breaker    *circuitBreaker
+	cache      Cache
+	mutableTTL time.Duration
+	flights    flightGroup
+	levels     levelMemo
+	// finalLevel and finalTime, in unix nanoseconds, are the highest level
+	// and the latest time known to be final
+	finalLevel atomic.Uint64
+	finalTime  atomic.Int64
+	metrics    Metrics
+	logger     *slog.Logger
+
+	client      *http.Client
+	middlewares []Middleware
+	transport   Doer
+
+	// optionErr is the error of the first invalid option, returned by every
+	// request of the client
+	optionErr error
+	// customEndpoint is set by the options replacing the endpoint of the
+	// network
+	customEndpoint bool
+}
+
+// Option configures a TZKT client
+type Option func(*TZKT)
 
-	client *http.Client
+// WithBaseURL points the client to a TzKT API served at the given url,
+// e.g. "http://tzkt.internal:5000". A path in the url is used as the prefix
+// of every api path.
+func WithBaseURL(baseURL string) Option {
+	u, err := parseBaseURL(baseURL)
+
+	return func(c *TZKT) {
+		if err != nil {
+			c.invalidOption(err)
+			return
+		}
+
+		if u.Scheme != "" {
+			c.scheme = u.Scheme
+		}
+		c.endpoint = u.Host
+		c.basePath = u.Path
+		c.customEndpoint = true
+	}
 }
 
-func New(network string) *TZKT {
-	endpoint := "api.mainnet.tzkt.io"
-	if network == "testnet" {
-		endpoint = "api.ghostnet.tzkt.io"
+// parseBaseURL parses the base url of a TzKT API, without trailing slash
+func parseBaseURL(baseURL string) (*url.URL, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%w: base url %q", ErrInvalidOption, baseURL)
 	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	return u, nil
+}
+
+// invalidOption records the error of an invalid option, unless an earlier
+// option was already invalid
+func (c *TZKT) invalidOption(err error) {
+	if c.optionErr == nil {
+		c.optionErr = err
+	}
+}
+
+// WithEndpoint sets the host (and optionally the port) of the TzKT API
+func WithEndpoint(endpoint string) Option {
+	return func(c *TZKT) {
+		c.endpoint = endpoint
+		c.customEndpoint = true
+	}
+}
+
+// WithScheme sets the url scheme used to reach the TzKT API. Defaults to https.
+func WithScheme(scheme string) Option {
+	return func(c *TZKT) {
+		c.scheme = scheme
+	}
+}
+
+// WithHTTPClient replaces the default http client which has a one-minute timeout
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *TZKT) {
+		c.client = client
+	}
+}
 
-	return &TZKT{
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *TZKT) {
+		c.userAgent = userAgent
+	}
+}
+
+// New returns a TZKT client for the given network. Known networks are mainnet,
+// ghostnet (or testnet), oxfordnet and parisnet; any other value falls back to
+// mainnet. Use options to reach a self-hosted or private TzKT instance. When an
+// option is invalid, every request of the client fails with its error; use
+// NewWithOptions to get the error at once.
+func New(network string, options ...Option) *TZKT {
+	endpoint, ok := networkEndpoints[network]
+	if !ok {
+		endpoint = networkEndpoints["mainnet"]
+	}
+
+	c := &TZKT{
 		client: &http.Client{
 			Timeout: time.Minute,
 		},
+		scheme:   "https",
 		endpoint: endpoint,
 	}
+
+	for _, o := range options {
+		o(c)
+	}
+
+	c.transport = c.doer()
+
+	return c
+}
+
+// NewWithOptions is like New but returns an error matching ErrInvalidOption
+// when an option is invalid, e.g. a base url read from the configuration, or
+// when the network is unknown and no option gives the endpoint to use instead
+func NewWithOptions(network string, options ...Option) (*TZKT, error) {
+	c := New(network, options...)
+	if c.optionErr != nil {
+		return nil, c.optionErr
+	}
+
+	if _, ok := networkEndpoints[network]; !ok && !c.customEndpoint {
+		return nil, fmt.Errorf("%w: unknown network %q", ErrInvalidOption, network)
+	}
+
+	return c, nil
 }
 
+// apiURL returns the absolute url of an api path on the configured endpoint
+func (c *TZKT) apiURL(path, rawQuery string) url.URL {
+	return url.URL{
+		Scheme:   c.scheme,
+		Host:     c.endpoint,
+		Path:     c.basePath + path,
+		RawQuery: rawQuery,
+	}
+}
+
+// errNoContent is returned by request for an empty response, which TzKT sends
+// with a 204 when the single item looked up does not exist
+var errNoContent = fmt.Errorf("empty response: %w", ErrNotFound)
+
+// request sends the request and decodes a successful response into responseData
 func (c *TZKT) request(req *http.Request, responseData interface{}) error {
-	resp, err := c.client.Do(req)
+	body, err := c.get(req)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		// close the body only when we return an error
-		defer resp.Body.Close()
-		if resp.StatusCode == http.StatusTooManyRequests {
-			return ErrTooManyRequest
+	// nothing to decode, e.g. the level of a time before the first block
+	if len(body) == 0 {
+		return errNoContent
+	}
+
+	if err := json.Unmarshal(body, responseData); err != nil {
+		if c.metrics != nil {
+			c.metrics.ObserveDecodeFailure(methodName(req.Context()))
 		}
 
-		errResp, err := io.ReadAll(resp.Body)
+		c.log(req.Context(), slog.LevelError, "tzkt response can not be decoded",
+			slog.String("url", req.URL.String()),
+			slog.String("error", err.Error()),
+			slog.String("body", snippet(body, err)),
+		)
+
+		return err
+	}
+
+	return nil
+}
+
+// get returns the body of a successful response to the request, from the
+// cache of the client when possible. Identical requests in progress at the
+// same time share a single api call.
+func (c *TZKT) get(req *http.Request) ([]byte, error) {
+	key := req.URL.String()
+
+	if c.cache != nil {
+		if body, ok := c.cache.Get(key); ok {
+			return body, nil
+		}
+	}
+
+	return c.flights.do(req.Context(), key, func() ([]byte, error) {
+		body, err := c.send(req)
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		if c.cache != nil {
+			if ttl, ok := c.cacheTTL(req.Context(), body); ok {
+				c.cache.Set(key, body, ttl)
+			}
+		}
+
+		return body, nil
+	})
+}
+
+// send sends the request and returns the body of a successful response.
+// Every attempt waits for the rate limiter, and failed attempts are retried
+// according to the retry policy of the client.
+func (c *TZKT) send(req *http.Request) ([]byte, error) {
+	if c.optionErr != nil {
+		return nil, c.optionErr
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		body, retry, retryAfter, err := c.attempt(req.WithContext(withAttempt(req.Context(), attempt)))
+		if err == nil || !retry || attempt >= c.retry.MaxAttempts {
+			return body, err
+		}
+
+		if c.metrics != nil {
+			c.metrics.ObserveRetry(methodName(req.Context()))
+		}
+
+		delay := c.retry.backoff(attempt, retryAfter)
+		c.log(req.Context(), slog.LevelWarn, "retrying tzkt request",
+			slog.String("url", req.URL.String()),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt performs an attempt of the request unless the circuit breaker of the
+// client is open, and records its outcome in the circuit breaker
+func (c *TZKT) attempt(req *http.Request) ([]byte, bool, time.Duration, error) {
+	if c.breaker == nil {
+		return c.failover(req)
+	}
+
+	if err := c.breaker.allow(); err != nil {
+		return nil, false, 0, err
+	}
+
+	body, retry, retryAfter, err := c.failover(req)
+
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		c.breaker.cancel()
+	case c.breaker.done(retry && !errors.Is(err, ErrTooManyRequest)):
+		c.log(req.Context(), slog.LevelWarn, "tzkt circuit breaker opened",
+			slog.String("url", req.URL.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	return body, retry, retryAfter, err
+}
+
+// do performs a single attempt of the request. It reports whether a failed
+// attempt is worth retrying and the delay requested by the server, if any.
+func (c *TZKT) do(req *http.Request) ([]byte, bool, time.Duration, error) {
+	statusCode := 0
+	start := time.Now()
+	defer func() {
+		if c.metrics != nil {
+			c.metrics.ObserveRequest(methodName(req.Context()), statusCode, time.Since(start))
+		}
+
+		c.log(req.Context(), slog.LevelDebug, "tzkt request",
+			slog.String("url", req.URL.String()),
+			slog.Int("attempt", Attempt(req.Context())),
+			slog.Int("status", statusCode),
+			slog.Duration("duration", time.Since(start)),
+		)
+	}()
+
+	resp, err := c.transport.Do(req.Clone(req.Context()))
+	if err != nil {
+		// network errors are transient unless the caller gave up
+		return nil, req.Context().Err() == nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	statusCode = resp.StatusCode
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if decode := streamDecoder(req.Context()); decode != nil {
+			// never retried, the decoded items are already handed out
+			return nil, false, 0, decode(resp.Body)
 		}
+	}
 
-		return fmt.Errorf("tzkt api error: %s", errResp)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, req.Context().Err() == nil, 0, err
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&responseData)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := newAPIError(resp, body)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			c.log(req.Context(), slog.LevelWarn, "tzkt rate limit exceeded",
+				slog.String("url", req.URL.String()),
+				slog.Duration("retryAfter", apiErr.RetryAfter),
+			)
+		}
+
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+
+		return nil, retry, apiErr.RetryAfter, apiErr
+	}
 
-	return err
+	return body, false, 0, nil
 }
@@ -1,15 +1,157 @@
 package tzkt
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/bitmark-inc/tzkt-go/tzkttest"
 )
 
+// fixturesDir holds the responses of the public api replayed by the tests of
+// real payloads, e.g. string encoded formats, array mime types and huge
+// supplies. Record them again with go test -tags live.
+const fixturesDir = "testdata/fixtures"
+
+// newReplayClient returns a client of a public network replaying the responses
+// recorded in fixturesDir, or recording them when the tests are built with the
+// live tag. The test is skipped when nothing was recorded for the network yet.
+func newReplayClient(t *testing.T, network string) *TZKT {
+	t.Helper()
+
+	mode := tzkttest.ModeReplay
+	if recordFixtures {
+		mode = tzkttest.ModeRecord
+	} else {
+		host, ok := networkEndpoints[network]
+		if !ok {
+			host = networkEndpoints["mainnet"]
+		}
+		if _, err := os.Stat(filepath.Join(fixturesDir, host)); os.IsNotExist(err) {
+			t.Skipf("no fixtures recorded for %s, record them with go test -tags live", host)
+		}
+	}
+
+	rec := tzkttest.NewRecorder(fixturesDir, mode, nil)
+
+	return New(network, WithHTTPClient(&http.Client{Transport: rec}))
+}
+
+func TestNewNetworks(t *testing.T) {
+	assert.Equal(t, "api.mainnet.tzkt.io", New("").endpoint)
+	assert.Equal(t, "api.ghostnet.tzkt.io", New("testnet").endpoint)
+	assert.Equal(t, "api.parisnet.tzkt.io", New("parisnet").endpoint)
+
+	tc := New("", WithEndpoint("localhost:5000"), WithScheme("http"))
+	u := tc.apiURL("/v1/head", "")
+	assert.Equal(t, "http://localhost:5000/v1/head", u.String())
+}
+
+func TestNewWithBaseURL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tzkt/v1/blocks/2023-09-29T03:17:35Z/level", r.URL.Path)
+		assert.Equal(t, "tzkt-go-test", r.Header.Get("User-Agent"))
+		fmt.Fprint(w, "4030701")
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL+"/tzkt/"), WithHTTPClient(ts.Client()), WithUserAgent("tzkt-go-test"))
+
+	level, err := tc.GetLevelByTime(time.Unix(1695957455, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4030701), level)
+}
+
+func TestNewWithOptions(t *testing.T) {
+	tc, err := NewWithOptions("", WithBaseURL("http://localhost:5000/tzkt/"))
+	assert.NoError(t, err)
+	u := tc.apiURL("/v1/head", "")
+	assert.Equal(t, "http://localhost:5000/tzkt/v1/head", u.String())
+
+	_, err = NewWithOptions("", WithBaseURL("localhost:5000"))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	_, err = NewWithOptions("", WithEndpoints(nil, DefaultFailoverPolicy))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	_, err = NewWithOptions("", WithEndpoints([]string{"http://tzkt1:5000", "%"}, DefaultFailoverPolicy))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	// an unknown network does not fall back to mainnet
+	_, err = NewWithOptions("mainet")
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	tc, err = NewWithOptions("ghostnet")
+	assert.NoError(t, err)
+	u = tc.apiURL("/v1/head", "")
+	assert.Equal(t, "https://api.ghostnet.tzkt.io/v1/head", u.String())
+
+	tc, err = NewWithOptions("private", WithEndpoint("tzkt.internal:5000"))
+	assert.NoError(t, err)
+	u = tc.apiURL("/v1/head", "")
+	assert.Equal(t, "https://tzkt.internal:5000/v1/head", u.String())
+
+	_, err = NewWithOptions("private", WithEndpoints([]string{"http://tzkt1:5000", "http://tzkt2:5000"}, DefaultFailoverPolicy))
+	assert.NoError(t, err)
+}
+
+func TestNewWithInvalidOption(t *testing.T) {
+	tc := New("", WithBaseURL("http://%zz"))
+
+	_, err := tc.GetHead()
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	err = tc.StreamTokenTransfersByLevel("100", 0, 10, func(TokenTransfer) error { return nil })
+	assert.ErrorIs(t, err, ErrInvalidOption)
+}
+
+func TestRequestWithContextDeadline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := tc.GetContractTokenWithContext(ctx, "KT1LjmAdYQCLBjwv4S2oFkEzyHVkomAf5MrW", "24216")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestEmptyResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL))
+
+	_, err := tc.GetLevelByTime(time.Unix(1695957455, 0))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = tc.GetHead()
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = tc.GetAccountBalanceAtLevel("tz1a", 100)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// the status of an unknown transaction is unknown, not missing
+	status, err := tc.GetTransactionStatusByTx("oo1")
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+}
+
 func TestGetTxStatus(t *testing.T) {
-	tc := New("testnet")
+	tc := newReplayClient(t, "testnet")
 
 	status, err := tc.GetTransactionStatusByTx("onk7fqQf7NNvG9BR5DFchnj4pKb9f5RdgkF89wMmUERLve2gS6N")
 	assert.NoError(t, err)
@@ -17,7 +159,7 @@ func TestGetTxStatus(t *testing.T) {
 }
 
 func TestGetLevelByTime(t *testing.T) {
-	tc := New("testnet")
+	tc := newReplayClient(t, "testnet")
 
 	level, err := tc.GetLevelByTime(time.Unix(1695957455, 0))
 	fmt.Println(level)
@@ -26,7 +168,7 @@ func TestGetLevelByTime(t *testing.T) {
 }
 
 func TestGetTxStatusNotConfirmed(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	status, err := tc.GetTransactionStatusByTx("onk7fqQf7NNvG9BR5DFchnj4pKb9f5RdgkF89wMmUERLve2gS6N")
 	assert.NoError(t, err)
@@ -34,7 +176,7 @@ func TestGetTxStatusNotConfirmed(t *testing.T) {
 }
 
 func TestGetContractToken(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	token, err := tc.GetContractToken("KT1LjmAdYQCLBjwv4S2oFkEzyHVkomAf5MrW", "24216")
 	assert.NoError(t, err)
@@ -53,7 +195,7 @@ func TestGetContractToken(t *testing.T) {
 }
 
 func TestRetrieveTokens(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	ownedTokens, err := tc.RetrieveTokens("tz1RBi5DCVBYh1EGrcoJszkte1hDjrFfXm5C", time.Time{}, 0)
 	assert.NoError(t, err)
@@ -62,7 +204,7 @@ func TestRetrieveTokens(t *testing.T) {
 }
 
 func TestGetTokenTransfers(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	transfers, err := tc.GetTokenTransfers("KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi", "905625", 0)
 	assert.NoError(t, err)
@@ -74,7 +216,7 @@ func TestGetTokenTransfers(t *testing.T) {
 }
 
 func TestGetTransaction(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	transaction, err := tc.GetTransaction(251825029644288)
 	assert.NoError(t, err)
@@ -82,7 +224,7 @@ func TestGetTransaction(t *testing.T) {
 }
 
 func TestGetTokenActivityTime(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	activityTime, err := tc.GetTokenLastActivityTime("KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi", "905625")
 	assert.NoError(t, err)
@@ -92,7 +234,7 @@ func TestGetTokenActivityTime(t *testing.T) {
 }
 
 func TestGetTokenTransfersCount(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	count, err := tc.GetTokenTransfersCount("KT1KEa8z6vWXDJrVqtMrAeDVzsvxat3kHaCE", "401199")
 	assert.NoError(t, err)
@@ -100,7 +242,7 @@ func TestGetTokenTransfersCount(t *testing.T) {
 }
 
 func TestGetTokenActivityTimeWithLimit200(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	activityTime, err := tc.GetTokenLastActivityTime("KT1KEa8z6vWXDJrVqtMrAeDVzsvxat3kHaCE", "401199")
 	assert.NoError(t, err)
@@ -113,7 +255,7 @@ func TestGetTokenActivityTimeWithLimit200(t *testing.T) {
 }
 
 func TestGetTokenActivityTimeNotExist(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	activityTime, err := tc.GetTokenLastActivityTime("KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi", "0")
 	assert.Error(t, err, "no activities for this token")
@@ -121,7 +263,7 @@ func TestGetTokenActivityTimeNotExist(t *testing.T) {
 }
 
 func TestGetTokenBalanceForOwner(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	owner, lastTime, err := tc.GetTokenBalanceAndLastTimeForOwner("KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton", "751194", "tz1bpvbjRGW1XHkALp4hFee6PKbnZCcoN9hE")
 	assert.NoError(t, err)
@@ -130,7 +272,7 @@ func TestGetTokenBalanceForOwner(t *testing.T) {
 }
 
 func TestGetArtworkMIMEType(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	token, err := tc.GetContractToken("KT1XXcp2U2vAn4dENmKjJkyYb8svTEf2DxTY", "0")
 	assert.NoError(t, err)
@@ -146,7 +288,7 @@ func TestGetArtworkMIMEType(t *testing.T) {
 	assert.Equal(t, mimeType, "image/jpeg")
 }
 func TestGetMIMETypeInArrayFormat(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	token, err := tc.GetContractToken("KT1Q4SBM941oAeu69v8LsrfwSiEkhMWJiVrp", "105353509316641797498497312618436889009736347208140239997663486800489418099672")
 	assert.NoError(t, err)
@@ -156,7 +298,7 @@ func TestGetMIMETypeInArrayFormat(t *testing.T) {
 }
 
 func TestHugeAmount(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	accountTokenTime, err := time.Parse(time.RFC3339, "2022-10-01T09:00:00Z")
 	assert.NoError(t, err)
@@ -170,7 +312,7 @@ func TestHugeAmount(t *testing.T) {
 }
 
 func TestGetTokenOwners(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	var startTime time.Time
 	var querLimit = 50
@@ -182,18 +324,23 @@ func TestGetTokenOwners(t *testing.T) {
 	assert.Equal(t, owners[0].LastTime.Format(time.RFC3339), "2023-04-20T07:09:01Z")
 }
 
-func TestGetTokenOwnersNow(t *testing.T) {
-	tc := New("")
+func TestGetTokenOwnersSince(t *testing.T) {
+	tc := newReplayClient(t, "")
 
 	var querLimit = 50
 
-	owners, err := tc.GetTokenOwners("KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton", "784317", querLimit, time.Now().Add(-time.Hour))
+	// a fixed time keeps the request, and so its fixture, stable
+	since := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	owners, err := tc.GetTokenOwners("KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton", "784317", querLimit, since)
 	assert.NoError(t, err)
-	assert.Len(t, owners, 0)
+	for _, o := range owners {
+		assert.True(t, o.LastTime.After(since))
+	}
 }
 
 func TestGetBigMapPointerForContractTokenMetadata(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	p, err := tc.GetBigMapPointerForContractTokenMetadata("KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi")
 	assert.NoError(t, err)
@@ -201,7 +348,7 @@ func TestGetBigMapPointerForContractTokenMetadata(t *testing.T) {
 }
 
 func TestGetBigMapValueByPointer(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	p, err := tc.GetBigMapValueByPointer(149772, "589146")
 	assert.NoError(t, err)
@@ -209,7 +356,7 @@ func TestGetBigMapValueByPointer(t *testing.T) {
 }
 
 func TestGetTokenBalanceOfOwner(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	value, err := tc.GetTokenBalanceOfOwner("KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton", "818282", "tz1UaGzw3MRwn7G9WQ5rRDs8tMCPqNw2JyQE")
 	assert.NoError(t, err)
@@ -217,7 +364,7 @@ func TestGetTokenBalanceOfOwner(t *testing.T) {
 }
 
 func TestGetBigMapsByContractAndPath(t *testing.T) {
-	tc := New("")
+	tc := newReplayClient(t, "")
 
 	ptr, err := tc.GetBigMapsByContractAndPath("KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton", "token_metadata")
 	assert.NoError(t, err)
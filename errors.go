@@ -0,0 +1,82 @@
+package tzkt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrTooManyRequest = fmt.Errorf("too many requests")
+
+	// ErrNotFound is matched by errors for missing resources, either a 404
+	// from the api or an empty result for a lookup of a single item
+	ErrNotFound = errors.New("not found")
+	// ErrBadRequest is matched by errors for requests rejected with a 400
+	ErrBadRequest = errors.New("bad request")
+	// ErrCircuitOpen is returned without calling the api while the circuit
+	// breaker of the client is open
+	ErrCircuitOpen = errors.New("tzkt circuit breaker is open")
+	// ErrInvalidOption is matched by the errors of a client created with an
+	// invalid option, e.g. a malformed base url
+	ErrInvalidOption = errors.New("invalid option")
+)
+
+// APIError is returned when the TzKT API responds with a non-200 status.
+// Use errors.Is with ErrNotFound, ErrBadRequest or ErrTooManyRequest to
+// check for the common cases.
+type APIError struct {
+	StatusCode int
+	URL        string
+
+	// Code, Message and Errors are read from the json error body of TzKT, e.g.
+	// {"code":400,"errors":{"limit":"The value must be between 0 and 10000"}}
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Errors  map[string]string `json:"errors"`
+
+	// Body is the raw response body
+	Body []byte `json:"-"`
+	// RetryAfter is the delay requested by the server through the Retry-After header
+	RetryAfter time.Duration `json:"-"`
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	e := &APIError{}
+
+	// the body is not always json, e.g. when a proxy is in front of the api
+	if err := json.Unmarshal(body, e); err != nil {
+		e = &APIError{}
+	}
+
+	e.StatusCode = resp.StatusCode
+	e.URL = resp.Request.URL.String()
+	e.Body = body
+	e.RetryAfter = parseRetryAfter(resp.Header)
+
+	return e
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return ErrTooManyRequest.Error()
+	}
+
+	return fmt.Sprintf("tzkt api error: %d %s", e.StatusCode, e.Body)
+}
+
+// Is makes errors.Is match the sentinel errors of the status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrTooManyRequest:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+package tzkt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"code":400,"errors":{"limit":"The value must be between 0 and 10000"}}`)
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL))
+
+	_, err := tc.GetTokenTransfers("KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi", "0", 20000)
+	assert.ErrorIs(t, err, ErrBadRequest)
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, 400, apiErr.Code)
+	assert.Equal(t, "The value must be between 0 and 10000", apiErr.Errors["limit"])
+	assert.Contains(t, apiErr.URL, "/v1/tokens/transfers?")
+}
+
+func TestAPIErrorNotJSON(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, "<html>slow down</html>")
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL))
+
+	_, err := tc.GetContractToken("KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi", "0")
+	assert.ErrorIs(t, err, ErrTooManyRequest)
+	assert.Equal(t, "too many requests", err.Error())
+}
+
+func TestEmptyResultNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "[]")
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL))
+
+	_, err := tc.GetContractToken("KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi", "0")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, "token not found", err.Error())
+
+	_, err = tc.GetBigMapValueByPointer(149772, "0")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	var apiErr *APIError
+	assert.False(t, errors.As(err, &apiErr))
+}
@@ -0,0 +1,267 @@
+package tzkt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// probeTimeout bounds the requests checking whether an endpoint is back
+const probeTimeout = 10 * time.Second
+
+// FailoverPolicy defines how the requests are spread over several endpoints
+type FailoverPolicy struct {
+	// RoundRobin spreads the requests over all the healthy endpoints instead
+	// of sending them to the first healthy one
+	RoundRobin bool
+	// ProbeInterval is how long an endpoint stays unhealthy before it is
+	// probed again
+	ProbeInterval time.Duration
+}
+
+// DefaultFailoverPolicy sends the requests to the first healthy endpoint and
+// probes the unhealthy ones every 30 seconds
+var DefaultFailoverPolicy = FailoverPolicy{
+	ProbeInterval: 30 * time.Second,
+}
+
+// EndpointStatus is the health of an endpoint of the client
+type EndpointStatus struct {
+	URL     string
+	Healthy bool
+	// Since is when the endpoint became unhealthy
+	Since time.Time
+	// Err is the failure which made the endpoint unhealthy
+	Err error
+}
+
+// WithEndpoints sends the requests to several TzKT APIs given by their base
+// urls, in order of preference. A request failing with a network error or a
+// 5xx response on an endpoint is sent again to the next one, and the failed
+// endpoint is avoided until it answers a probe of its head block. Unhealthy
+// endpoints are still used when no endpoint is healthy.
+func WithEndpoints(baseURLs []string, policy FailoverPolicy) Option {
+	if len(baseURLs) == 0 {
+		return func(c *TZKT) {
+			c.invalidOption(fmt.Errorf("%w: no endpoints", ErrInvalidOption))
+		}
+	}
+
+	endpoints := make([]*endpoint, len(baseURLs))
+	for i, baseURL := range baseURLs {
+		u, err := parseBaseURL(baseURL)
+		if err != nil {
+			return func(c *TZKT) {
+				c.invalidOption(err)
+			}
+		}
+		endpoints[i] = &endpoint{url: u, healthy: true}
+	}
+
+	if policy.ProbeInterval <= 0 {
+		policy.ProbeInterval = DefaultFailoverPolicy.ProbeInterval
+	}
+
+	// the urls of the requests are built for the first endpoint
+	primary := WithBaseURL(baseURLs[0])
+
+	return func(c *TZKT) {
+		primary(c)
+		c.endpoints = &endpointPool{endpoints: endpoints, policy: policy}
+	}
+}
+
+// Endpoints returns the health of the endpoints of the client, for health
+// checks. It is empty unless the client uses WithEndpoints.
+func (c *TZKT) Endpoints() []EndpointStatus {
+	if c.endpoints == nil {
+		return nil
+	}
+
+	return c.endpoints.status()
+}
+
+type endpoint struct {
+	url *url.URL
+
+	healthy bool
+	since   time.Time
+	err     error
+	probing bool
+}
+
+// rewrite points u, built for the base path of the client, to the endpoint
+func (e *endpoint) rewrite(u *url.URL, basePath string) {
+	u.Scheme = e.url.Scheme
+	u.Host = e.url.Host
+	u.Path = e.url.Path + strings.TrimPrefix(u.Path, basePath)
+	u.RawPath = ""
+}
+
+// endpointPool tracks the health of the endpoints of a client
+type endpointPool struct {
+	sync.Mutex
+
+	endpoints []*endpoint
+	policy    FailoverPolicy
+	next      int
+}
+
+// order returns the endpoints to try for a request, healthy ones first, and
+// the unhealthy endpoints due for a probe
+func (p *endpointPool) order() ([]*endpoint, []*endpoint) {
+	p.Lock()
+	defer p.Unlock()
+
+	var healthy, unhealthy, probes []*endpoint
+	for _, e := range p.endpoints {
+		if e.healthy {
+			healthy = append(healthy, e)
+			continue
+		}
+
+		unhealthy = append(unhealthy, e)
+		if !e.probing && time.Since(e.since) >= p.policy.ProbeInterval {
+			e.probing = true
+			probes = append(probes, e)
+		}
+	}
+
+	if p.policy.RoundRobin && len(healthy) > 1 {
+		start := p.next % len(healthy)
+		healthy = append(healthy[start:], healthy[:start]...)
+		p.next++
+	}
+
+	return append(healthy, unhealthy...), probes
+}
+
+func (p *endpointPool) markHealthy(e *endpoint) {
+	p.Lock()
+	defer p.Unlock()
+
+	e.healthy = true
+	e.err = nil
+}
+
+func (p *endpointPool) markUnhealthy(e *endpoint, err error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if e.healthy {
+		e.healthy = false
+		e.since = time.Now()
+	}
+	e.err = err
+}
+
+func (p *endpointPool) status() []EndpointStatus {
+	p.Lock()
+	defer p.Unlock()
+
+	status := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		status[i] = EndpointStatus{
+			URL:     e.url.String(),
+			Healthy: e.healthy,
+			Since:   e.since,
+			Err:     e.err,
+		}
+	}
+
+	return status
+}
+
+// failover performs an attempt of the request on the endpoints of the client
+// until one of them answers it
+func (c *TZKT) failover(req *http.Request) ([]byte, bool, time.Duration, error) {
+	if c.endpoints == nil {
+		return c.do(req)
+	}
+
+	endpoints, probes := c.endpoints.order()
+	for _, e := range probes {
+		go c.probe(e)
+	}
+
+	var (
+		body       []byte
+		retry      bool
+		retryAfter time.Duration
+		err        error
+	)
+
+	for _, e := range endpoints {
+		r := req.Clone(req.Context())
+		e.rewrite(r.URL, c.basePath)
+
+		body, retry, retryAfter, err = c.do(r)
+
+		// only failures of the endpoint itself are worth trying elsewhere
+		if err == nil || !retry || errors.Is(err, ErrTooManyRequest) {
+			c.endpoints.markHealthy(e)
+			return body, retry, retryAfter, err
+		}
+
+		c.endpoints.markUnhealthy(e, err)
+		c.log(req.Context(), slog.LevelWarn, "tzkt endpoint failed",
+			slog.String("endpoint", e.url.String()),
+			slog.String("error", err.Error()),
+		)
+
+		if req.Context().Err() != nil {
+			break
+		}
+	}
+
+	return body, retry, retryAfter, err
+}
+
+// probe checks whether an unhealthy endpoint is back by requesting its head block
+func (c *TZKT) probe(e *endpoint) {
+	ctx, cancel := context.WithTimeout(withMethod(context.Background(), "probe"), probeTimeout)
+	defer cancel()
+
+	u := *e.url
+	u.Path += "/v1/head"
+
+	err := func() error {
+		req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return err
+		}
+		if c.userAgent != "" {
+			req.Header.Set("User-Agent", c.userAgent)
+		}
+
+		resp, err := c.transport.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return &APIError{StatusCode: resp.StatusCode, URL: u.String()}
+		}
+
+		return nil
+	}()
+
+	c.endpoints.Lock()
+	defer c.endpoints.Unlock()
+
+	e.probing = false
+	if err == nil {
+		e.healthy = true
+		e.err = nil
+	} else {
+		e.since = time.Now()
+		e.err = err
+	}
+}
@@ -0,0 +1,148 @@
+package tzkt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type replica struct {
+	*httptest.Server
+
+	calls  int32
+	status int32
+}
+
+func newReplica(t *testing.T, count int) *replica {
+	r := &replica{status: http.StatusOK}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&r.calls, 1)
+		if status := atomic.LoadInt32(&r.status); status != http.StatusOK {
+			w.WriteHeader(int(status))
+			return
+		}
+		if req.URL.Path == "/v1/head" {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		fmt.Fprint(w, count)
+	}))
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func TestFailover(t *testing.T) {
+	a, b := newReplica(t, 1), newReplica(t, 2)
+	atomic.StoreInt32(&a.status, http.StatusBadGateway)
+
+	c := New("", WithEndpoints([]string{a.URL, b.URL}, DefaultFailoverPolicy))
+
+	count, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	status := c.Endpoints()
+	assert.False(t, status[0].Healthy)
+	var apiErr *APIError
+	assert.ErrorAs(t, status[0].Err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.True(t, status[1].Healthy)
+
+	// the unhealthy endpoint is avoided
+	_, err = c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&a.calls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&b.calls))
+}
+
+func TestFailoverNotOnClientErrors(t *testing.T) {
+	a, b := newReplica(t, 1), newReplica(t, 2)
+	atomic.StoreInt32(&a.status, http.StatusBadRequest)
+
+	c := New("", WithEndpoints([]string{a.URL, b.URL}, DefaultFailoverPolicy))
+
+	_, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.ErrorIs(t, err, ErrBadRequest)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&b.calls))
+	assert.True(t, c.Endpoints()[0].Healthy)
+}
+
+func TestFailoverAllUnhealthy(t *testing.T) {
+	a := newReplica(t, 1)
+	b := newReplica(t, 2)
+	b.Close()
+	atomic.StoreInt32(&a.status, http.StatusServiceUnavailable)
+
+	c := New("", WithEndpoints([]string{a.URL, b.URL}, DefaultFailoverPolicy))
+
+	_, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.Error(t, err)
+
+	// unhealthy endpoints are still tried when there is nothing else
+	atomic.StoreInt32(&a.status, http.StatusOK)
+	count, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.True(t, c.Endpoints()[0].Healthy)
+}
+
+func TestFailoverRoundRobin(t *testing.T) {
+	a, b, d := newReplica(t, 1), newReplica(t, 2), newReplica(t, 3)
+
+	c := New("", WithEndpoints([]string{a.URL, b.URL, d.URL}, FailoverPolicy{RoundRobin: true}))
+
+	var counts []int
+	for i := 0; i < 6; i++ {
+		count, err := c.GetTokenTransfersCount("KT1", "1")
+		assert.NoError(t, err)
+		counts = append(counts, count)
+	}
+	assert.Equal(t, []int{1, 2, 3, 1, 2, 3}, counts)
+}
+
+func TestFailoverProbe(t *testing.T) {
+	a, b := newReplica(t, 1), newReplica(t, 2)
+	atomic.StoreInt32(&a.status, http.StatusInternalServerError)
+
+	c := New("", WithEndpoints([]string{a.URL, b.URL}, FailoverPolicy{ProbeInterval: 10 * time.Millisecond}))
+
+	count, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	atomic.StoreInt32(&a.status, http.StatusOK)
+	time.Sleep(20 * time.Millisecond)
+
+	// the request triggering the probe is still served by the healthy endpoint
+	count, err = c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	assert.Eventually(t, func() bool { return c.Endpoints()[0].Healthy }, time.Second, 5*time.Millisecond)
+
+	count, err = c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestFailoverBasePath(t *testing.T) {
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		fmt.Fprint(w, "5")
+	}))
+	defer ts.Close()
+
+	c := New("", WithEndpoints([]string{"http://127.0.0.1:1/primary", ts.URL + "/replica/"}, DefaultFailoverPolicy))
+
+	count, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+	assert.Equal(t, "/replica/v1/tokens/transfers/count", path)
+}
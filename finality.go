@@ -0,0 +1,124 @@
+package tzkt
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// finalityMargin is how much older than the head of a synced indexer a block
+// has to be to be final
+const finalityMargin = 10 * time.Minute
+
+// isFinalTime reports whether the blocks produced before at are final, i.e.
+// the indexer is synced with the chain and its head is more than
+// finalityMargin past at. A lagging indexer answers the lookups of any later
+// time with its own head, which must not be taken for the final answer. The
+// head is only requested when at is after the times known to be final.
+func (c *TZKT) isFinalTime(ctx context.Context, at time.Time) bool {
+	if at.Before(c.knownFinalTime()) {
+		return true
+	}
+
+	// the head is never more recent than now
+	if time.Since(at) <= finalityMargin {
+		return false
+	}
+
+	return c.refreshFinality(ctx) && at.Before(c.knownFinalTime())
+}
+
+// isFinal reports whether the block of a level is final. The head, and the
+// level of its final time, are only requested when level is above the levels
+// known to be final.
+func (c *TZKT) isFinal(ctx context.Context, level uint64) bool {
+	if level <= c.finalLevel.Load() {
+		return true
+	}
+
+	if !c.refreshFinality(ctx) {
+		return false
+	}
+
+	// the last block produced before the final time
+	final, err := c.GetLevelByTimeWithContext(ctx, c.knownFinalTime().Add(-time.Second))
+	if err != nil {
+		return false
+	}
+	c.setFinalLevel(final)
+
+	return level <= final
+}
+
+// finalLevels reports whether the blocks of the levels in a response body,
+// an object or a list of objects with a level, are all final
+func (c *TZKT) finalLevels(ctx context.Context, body []byte) bool {
+	type leveled struct {
+		Level uint64 `json:"level"`
+	}
+
+	var items []leveled
+	if err := json.Unmarshal(body, &items); err != nil {
+		var item leveled
+		if err := json.Unmarshal(body, &item); err != nil {
+			return false
+		}
+		items = append(items, item)
+	}
+
+	var highest uint64
+	for _, item := range items {
+		if item.Level == 0 {
+			return false
+		}
+		if item.Level > highest {
+			highest = item.Level
+		}
+	}
+
+	return highest > 0 && c.isFinal(ctx, highest)
+}
+
+// refreshFinality records the final time of the current head of the indexer.
+// It reports false when the head is not available or the indexer is not
+// synced, in which case nothing more is known to be final.
+func (c *TZKT) refreshFinality(ctx context.Context) bool {
+	head, err := c.GetHeadWithContext(ctx)
+	if err != nil || !head.Synced {
+		return false
+	}
+
+	c.setFinalTime(head.Timestamp.Add(-finalityMargin))
+
+	return true
+}
+
+// knownFinalTime returns the latest time before which blocks are known to be
+// final, or the zero time
+func (c *TZKT) knownFinalTime() time.Time {
+	if t := c.finalTime.Load(); t != 0 {
+		return time.Unix(0, t)
+	}
+
+	return time.Time{}
+}
+
+// setFinalTime records that the blocks produced before t are final
+func (c *TZKT) setFinalTime(t time.Time) {
+	for {
+		known := c.finalTime.Load()
+		if t.UnixNano() <= known || c.finalTime.CompareAndSwap(known, t.UnixNano()) {
+			return
+		}
+	}
+}
+
+// setFinalLevel records that the blocks up to level are final
+func (c *TZKT) setFinalLevel(level uint64) {
+	for {
+		known := c.finalLevel.Load()
+		if level <= known || c.finalLevel.CompareAndSwap(known, level) {
+			return
+		}
+	}
+}
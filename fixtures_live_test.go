@@ -0,0 +1,7 @@
+//go:build live
+
+package tzkt
+
+// recordFixtures makes the tests of real payloads call the public api and
+// record its responses to fixturesDir
+const recordFixtures = true
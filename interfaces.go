@@ -0,0 +1,115 @@
+package tzkt
+
+import (
+	"context"
+	"time"
+)
+
+// TokenReader reads tokens, token balances and token transfers
+type TokenReader interface {
+	GetContractToken(contract, tokenID string) (Token, error)
+	GetContractTokenWithContext(ctx context.Context, contract, tokenID string) (Token, error)
+	GetTokenBalanceOfOwner(contract, tokenID, owner string) (int64, error)
+	GetTokenBalanceOfOwnerWithContext(ctx context.Context, contract, tokenID, owner string) (int64, error)
+	GetTokenOwners(contract, tokenID string, limit int, lastTime time.Time) ([]TokenOwner, error)
+	GetTokenOwnersWithContext(ctx context.Context, contract, tokenID string, limit int, lastTime time.Time) ([]TokenOwner, error)
+	GetTokenBalanceAndLastTimeForOwner(contract, tokenID, owner string) (int64, time.Time, error)
+	GetTokenBalanceAndLastTimeForOwnerWithContext(ctx context.Context, contract, tokenID, owner string) (int64, time.Time, error)
+	GetTokenLastActivityTime(contract, tokenID string) (time.Time, error)
+	GetTokenLastActivityTimeWithContext(ctx context.Context, contract, tokenID string) (time.Time, error)
+	GetTokenTransfers(contract, tokenID string, limit int) ([]TokenTransfer, error)
+	GetTokenTransfersWithContext(ctx context.Context, contract, tokenID string, limit int) ([]TokenTransfer, error)
+	GetTokenTransfersByLevel(level string, offset, limit int) ([]TokenTransfer, error)
+	GetTokenTransfersByLevelWithContext(ctx context.Context, level string, offset, limit int) ([]TokenTransfer, error)
+	StreamTokenTransfersByLevel(level string, offset, limit int, fn func(TokenTransfer) error) error
+	StreamTokenTransfersByLevelWithContext(ctx context.Context, level string, offset, limit int, fn func(TokenTransfer) error) error
+	GetTokenTransfersCount(contract, tokenID string) (int, error)
+	GetTokenTransfersCountWithContext(ctx context.Context, contract, tokenID string) (int, error)
+	RetrieveTokens(owner string, lastTime time.Time, offset int) ([]OwnedToken, error)
+	RetrieveTokensWithContext(ctx context.Context, owner string, lastTime time.Time, offset int) ([]OwnedToken, error)
+	IterateTokenTransfers(contract, tokenID string, pageSize int) *Iterator[TokenTransfer]
+	IterateTokenTransfersByLevel(level string, pageSize int) *Iterator[TokenTransfer]
+	IterateOwnedTokens(owner string, lastTime time.Time, pageSize int) *Iterator[OwnedToken]
+	GetTokenBalancesAtLevel(contract, tokenID, owner string, level uint64) ([]HistoricalTokenBalance, error)
+	GetTokenBalancesAtLevelWithContext(ctx context.Context, contract, tokenID, owner string, level uint64) ([]HistoricalTokenBalance, error)
+	GetTokenBalancesAtTime(contract, tokenID, owner string, at time.Time) ([]HistoricalTokenBalance, error)
+	GetTokenBalancesAtTimeWithContext(ctx context.Context, contract, tokenID, owner string, at time.Time) ([]HistoricalTokenBalance, error)
+	IterateTokenBalancesAtLevel(contract, tokenID, owner string, level uint64, pageSize int) *Iterator[HistoricalTokenBalance]
+}
+
+// OperationReader reads transactions
+type OperationReader interface {
+	GetTransactionStatusByTx(hash string) (*bool, error)
+	GetTransactionStatusByTxWithContext(ctx context.Context, hash string) (*bool, error)
+	GetTransactionByTx(hash string) ([]DetailedTransaction, error)
+	GetTransactionByTxWithContext(ctx context.Context, hash string) ([]DetailedTransaction, error)
+	GetTransaction(id uint64) (Transaction, error)
+	GetTransactionWithContext(ctx context.Context, id uint64) (Transaction, error)
+	GetTransactions(contracts []string, entrypoints []string, lastTime *time.Time, offset, limit int) ([]Transaction, error)
+	GetTransactionsWithContext(ctx context.Context, contracts []string, entrypoints []string, lastTime *time.Time, offset, limit int) ([]Transaction, error)
+	IterateTransactions(contracts []string, entrypoints []string, lastTime *time.Time, pageSize int) *Iterator[Transaction]
+}
+
+// BigmapReader reads bigmaps, their keys and their updates
+type BigmapReader interface {
+	GetBigMapValueByPointer(pointer int, key string) ([]byte, error)
+	GetBigMapValueByPointerWithContext(ctx context.Context, pointer int, key string) ([]byte, error)
+	GetBigMapPointersByContract(contract string, tags ...string) ([]int, error)
+	GetBigMapPointersByContractWithContext(ctx context.Context, contract string, tags ...string) ([]int, error)
+	GetBigMapsByContractAndPath(contract string, path string) (int, error)
+	GetBigMapsByContractAndPathWithContext(ctx context.Context, contract string, path string) (int, error)
+	GetBigMapPointerForContractTokenMetadata(contract string) (int, error)
+	GetBigMapPointerForContractTokenMetadataWithContext(ctx context.Context, contract string) (int, error)
+	GetTokenMetadataBigmapUpdatesByLevel(level string, offset, limit int) ([]BigmapUpdate, error)
+	GetTokenMetadataBigmapUpdatesByLevelWithContext(ctx context.Context, level string, offset, limit int) ([]BigmapUpdate, error)
+	StreamTokenMetadataBigmapUpdatesByLevel(level string, offset, limit int, fn func(BigmapUpdate) error) error
+	StreamTokenMetadataBigmapUpdatesByLevelWithContext(ctx context.Context, level string, offset, limit int, fn func(BigmapUpdate) error) error
+	IterateTokenMetadataBigmapUpdatesByLevel(level string, pageSize int) *Iterator[BigmapUpdate]
+}
+
+// BlockReader reads blocks
+type BlockReader interface {
+	GetHead() (Head, error)
+	GetHeadWithContext(ctx context.Context) (Head, error)
+	GetProtocol(code int) (Protocol, error)
+	GetProtocolWithContext(ctx context.Context, code int) (Protocol, error)
+	GetBlock(levelOrHash string, operations bool) (Block, error)
+	GetBlockWithContext(ctx context.Context, levelOrHash string, operations bool) (Block, error)
+	GetBlocks(filter BlockFilter) ([]Block, error)
+	GetBlocksWithContext(ctx context.Context, filter BlockFilter) ([]Block, error)
+	IterateBlocks(filter BlockFilter, pageSize int) *Iterator[Block]
+	GetLevelByTime(at time.Time) (uint64, error)
+	GetLevelByTimeWithContext(ctx context.Context, at time.Time) (uint64, error)
+	GetTimeByLevel(level uint64) (time.Time, error)
+	GetTimeByLevelWithContext(ctx context.Context, level uint64) (time.Time, error)
+	GetLevelRange(from, to time.Time) (LevelRange, error)
+	GetLevelRangeWithContext(ctx context.Context, from, to time.Time) (LevelRange, error)
+}
+
+// AccountReader reads accounts and their operations
+type AccountReader interface {
+	GetAccount(address string) (AccountInfo, error)
+	GetAccountWithContext(ctx context.Context, address string) (AccountInfo, error)
+	GetAccounts(filter AccountFilter) ([]AccountInfo, error)
+	GetAccountsWithContext(ctx context.Context, filter AccountFilter) ([]AccountInfo, error)
+	IterateAccounts(filter AccountFilter, pageSize int) *Iterator[AccountInfo]
+	GetAccountOperations(address string, filter OperationFilter) (Operations, error)
+	GetAccountOperationsWithContext(ctx context.Context, address string, filter OperationFilter) (Operations, error)
+	IterateAccountOperations(address string, filter OperationFilter, pageSize int) *Iterator[Operation]
+	GetAccountBalanceAtLevel(address string, level uint64) (int64, error)
+	GetAccountBalanceAtLevelWithContext(ctx context.Context, address string, level uint64) (int64, error)
+	GetAccountBalanceAtTime(address string, at time.Time) (int64, error)
+	GetAccountBalanceAtTimeWithContext(ctx context.Context, address string, at time.Time) (int64, error)
+}
+
+// Client is the whole read api of TzKT. Depend on it, or on one of the smaller
+// readers, instead of *TZKT to be able to use a fake in tests.
+type Client interface {
+	TokenReader
+	OperationReader
+	BigmapReader
+	BlockReader
+	AccountReader
+}
+
+var _ Client = (*TZKT)(nil)
@@ -0,0 +1,119 @@
+package tzkt
+
+import (
+	"context"
+	"net/http"
+)
+
+// defaultPageSize is the page size used by iterators when none is given
+const defaultPageSize = 100
+
+// Iterator walks through every item of a list endpoint page by page. Pages are
+// requested with the id based cursor of TzKT (offset.cr) so items are neither
+// skipped nor repeated when new data arrives during the iteration.
+//
+//	it := c.IterateTokenTransfers(contract, tokenID, 1000)
+//	for it.Next(ctx) {
+//		transfer := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator[T any] struct {
+	fetch func(ctx context.Context, cursor uint64, limit int) ([]T, error)
+	id    func(T) uint64
+
+	limit  int
+	cursor uint64
+	page   []T
+	item   T
+	last   bool
+	err    error
+}
+
+// NewIterator returns an Iterator requesting pages of limit items with fetch.
+// The cursor given to fetch is the id of the last item returned so far, as
+// given by id, or zero for the first page. It lets fakes of the Client
+// interfaces build iterators.
+func NewIterator[T any](limit int, id func(T) uint64, fetch func(ctx context.Context, cursor uint64, limit int) ([]T, error)) *Iterator[T] {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+
+	return &Iterator[T]{
+		fetch: fetch,
+		id:    id,
+		limit: limit,
+	}
+}
+
+// newListIterator returns an Iterator over the items of an api path filtered by
+// query. The requests are attributed to the client method named method.
+func newListIterator[T any](c *TZKT, method, path string, query *Query, limit int, id func(T) uint64) *Iterator[T] {
+	return NewIterator(limit, id, func(ctx context.Context, cursor uint64, limit int) ([]T, error) {
+		q := query.Clone().
+			SortAsc("id").
+			Limit(limit)
+
+		if cursor > 0 {
+			q.OffsetCursor(cursor)
+		}
+
+		u := c.apiURL(path, q.Encode())
+		ctx = withMethod(ctx, method)
+
+		var items []T
+
+		req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.request(req, &items); err != nil {
+			return nil, err
+		}
+
+		return items, nil
+	})
+}
+
+// Next advances the iterator to the next item, fetching a new page when needed.
+// It returns false when all items are consumed or an error occurs.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if len(it.page) == 0 {
+		if it.last {
+			return false
+		}
+
+		items, err := it.fetch(ctx, it.cursor, it.limit)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = items
+		it.last = len(items) < it.limit
+
+		if len(items) == 0 {
+			return false
+		}
+	}
+
+	it.item, it.page = it.page[0], it.page[1:]
+	it.cursor = it.id(it.item)
+
+	return true
+}
+
+// Item returns the current item
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
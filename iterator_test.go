@@ -0,0 +1,80 @@
+package tzkt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// transfersServer serves total token transfers with ids from 1 to total,
+// honoring the offset.cr and limit query parameters
+func transfersServer(t *testing.T, total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "id", q.Get("sort.asc"))
+		assert.Equal(t, "KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi", q.Get("token.contract"))
+
+		cursor, _ := strconv.Atoi(q.Get("offset.cr"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+
+		transfers := []TokenTransfer{}
+		for id := cursor + 1; id <= total && len(transfers) < limit; id++ {
+			transfers = append(transfers, TokenTransfer{ID: uint64(id), Level: uint64(id)})
+		}
+
+		assert.NoError(t, json.NewEncoder(w).Encode(transfers))
+	}))
+}
+
+func TestIterateTokenTransfers(t *testing.T) {
+	ts := transfersServer(t, 25)
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL))
+
+	it := tc.IterateTokenTransfers("KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi", "0", 10)
+
+	var ids []uint64
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Len(t, ids, 25)
+	assert.Equal(t, uint64(1), ids[0])
+	assert.Equal(t, uint64(25), ids[24])
+}
+
+func TestIterateTokenTransfersExactPages(t *testing.T) {
+	ts := transfersServer(t, 20)
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL))
+
+	it := tc.IterateTokenTransfers("KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi", "0", 10)
+
+	count := 0
+	for it.Next(context.Background()) {
+		count++
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 20, count)
+}
+
+func TestIterateError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL))
+
+	it := tc.IterateOwnedTokens("tz1RBi5DCVBYh1EGrcoJszkte1hDjrFfXm5C", time.Time{}, 0)
+	assert.False(t, it.Next(context.Background()))
+	assert.ErrorIs(t, it.Err(), ErrBadRequest)
+}
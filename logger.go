@@ -0,0 +1,59 @@
+package tzkt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+)
+
+// snippetSize is the size of the json snippets logged on decode failures
+const snippetSize = 256
+
+// WithLogger logs the activity of the client to logger: requests at debug
+// level, retries and rate limiting at warn level and responses which can not be
+// decoded at error level. The client is silent without a logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *TZKT) {
+		c.logger = logger
+	}
+}
+
+// log logs a message with the client method of ctx when the client has a logger
+func (c *TZKT) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if c.logger == nil || !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	c.logger.Log(ctx, level, msg, append([]any{slog.String("method", methodName(ctx))}, args...)...)
+}
+
+// snippet returns the part of body where decoding failed with err, truncated
+// to snippetSize bytes. It starts at the beginning of body when the failure
+// can not be located.
+func snippet(body []byte, err error) string {
+	var offset int64
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	}
+
+	start := max(0, int(offset)-snippetSize/2)
+	end := min(len(body), start+snippetSize)
+	start = max(0, min(start, end-snippetSize))
+
+	s := string(body[start:end])
+	if start > 0 {
+		s = "..." + s
+	}
+	if end < len(body) {
+		s += "..."
+	}
+
+	return s
+}
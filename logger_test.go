@@ -0,0 +1,99 @@
+package tzkt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeLogs(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var logs []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		logs = append(logs, entry)
+	}
+
+	return logs
+}
+
+func TestLogger(t *testing.T) {
+	attempt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt++
+		if attempt == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `[{"contract":{"address":"KT1"},"tokenId":"1","totalSupply":"1","metadata":{"formats":[{"uri":"ipfs://x","fileSize":"abc"}]}}]`)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	c := New("",
+		WithBaseURL(ts.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+
+	_, err := c.GetContractToken("KT1", "1")
+	assert.Error(t, err)
+
+	logs := decodeLogs(t, &buf)
+	assert.Len(t, logs, 5)
+
+	var messages []string
+	for _, entry := range logs {
+		assert.Equal(t, "GetContractToken", entry["method"])
+		messages = append(messages, fmt.Sprintf("%s %s", entry["level"], entry["msg"]))
+	}
+	assert.Equal(t, []string{
+		"WARN tzkt rate limit exceeded",
+		"DEBUG tzkt request",
+		"WARN retrying tzkt request",
+		"DEBUG tzkt request",
+		"ERROR tzkt response can not be decoded",
+	}, messages)
+
+	assert.Equal(t, float64(429), logs[1]["status"])
+	assert.Equal(t, float64(2), logs[3]["attempt"])
+	assert.Contains(t, logs[4]["body"], `"fileSize":"abc"`)
+}
+
+func TestLoggerLevel(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "1")
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	c := New("", WithBaseURL(ts.URL), WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+
+	_, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+}
+
+func TestSnippet(t *testing.T) {
+	body := []byte(`[` + strings.Repeat(`1,`, 300) + `x]`)
+
+	var v []int
+	err := json.Unmarshal(body, &v)
+	assert.Error(t, err)
+
+	s := snippet(body, err)
+	assert.True(t, strings.HasPrefix(s, "..."))
+	assert.True(t, strings.HasSuffix(s, "x]"))
+	assert.Len(t, s, snippetSize+3)
+
+	assert.Equal(t, "[1]", snippet([]byte("[1]"), fmt.Errorf("custom")))
+	assert.Equal(t, strings.Repeat("a", snippetSize)+"...", snippet(bytes.Repeat([]byte("a"), 1000), fmt.Errorf("custom")))
+}
@@ -0,0 +1,62 @@
+package tzkt
+
+import (
+	"sync"
+	"time"
+)
+
+// maxMemoizedLevels bounds the number of levels, and of times, remembered by
+// a levelMemo
+const maxMemoizedLevels = 10000
+
+// levelMemo remembers the levels of past times and the times of past levels,
+// which never change once their blocks are final. Each is emptied when full.
+// The zero value is ready to use.
+type levelMemo struct {
+	sync.Mutex
+
+	levels map[int64]uint64
+	times  map[uint64]time.Time
+}
+
+// get returns the level remembered for a time in seconds
+func (m *levelMemo) get(at time.Time) (uint64, bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	level, ok := m.levels[at.Unix()]
+
+	return level, ok
+}
+
+// set remembers the level of a time in seconds
+func (m *levelMemo) set(at time.Time, level uint64) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.levels == nil || len(m.levels) >= maxMemoizedLevels {
+		m.levels = map[int64]uint64{}
+	}
+	m.levels[at.Unix()] = level
+}
+
+// getTime returns the time remembered for a level
+func (m *levelMemo) getTime(level uint64) (time.Time, bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	at, ok := m.times[level]
+
+	return at, ok
+}
+
+// setTime remembers the time of a level
+func (m *levelMemo) setTime(level uint64, at time.Time) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.times == nil || len(m.times) >= maxMemoizedLevels {
+		m.times = map[uint64]time.Time{}
+	}
+	m.times[level] = at
+}
@@ -0,0 +1,70 @@
+package tzkt
+
+import (
+	"context"
+	"time"
+)
+
+// Metrics collects measurements of the api calls of a client. Calls are
+// labelled by the name of the client method making them, such as
+// GetTokenTransfers, rather than by url to keep the number of labels bounded.
+type Metrics interface {
+	// ObserveRequest is called after every attempt of a request with the status
+	// code of the response, or 0 when no response was received
+	ObserveRequest(method string, statusCode int, duration time.Duration)
+	// ObserveRetry is called before a failed request is attempted again
+	ObserveRetry(method string)
+	// ObserveDecodeFailure is called when a response can not be decoded
+	ObserveDecodeFailure(method string)
+}
+
+// WithMetrics reports the api calls of the client to metrics
+func WithMetrics(metrics Metrics) Option {
+	return func(c *TZKT) {
+		c.metrics = metrics
+	}
+}
+
+// unknownMethod labels the requests not made by a client method
+const unknownMethod = "unknown"
+
+type methodKey struct{}
+
+// withMethod attributes the requests made with the returned context to the
+// client method named method, unless they are already attributed to the
+// method calling it
+func withMethod(ctx context.Context, method string) context.Context {
+	if _, ok := ctx.Value(methodKey{}).(string); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, methodKey{}, method)
+}
+
+// methodName returns the client method the requests made with ctx belong to
+func methodName(ctx context.Context) string {
+	if method, ok := ctx.Value(methodKey{}).(string); ok {
+		return method
+	}
+
+	return unknownMethod
+}
+
+// Method returns the name of the client method which sent a request, given the
+// context of the request. It lets middlewares label requests like Metrics.
+func Method(ctx context.Context) string {
+	return methodName(ctx)
+}
+
+type attemptKey struct{}
+
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// Attempt returns which attempt of a request, starting at 1, is being sent,
+// given the context of the request. It is 0 outside of the client.
+func Attempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
@@ -0,0 +1,101 @@
+package tzkt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedMetrics struct {
+	sync.Mutex
+
+	requests []string
+	retries  []string
+	failures []string
+}
+
+func (m *recordedMetrics) ObserveRequest(method string, statusCode int, duration time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+	m.requests = append(m.requests, fmt.Sprintf("%s %d", method, statusCode))
+}
+
+func (m *recordedMetrics) ObserveRetry(method string) {
+	m.Lock()
+	defer m.Unlock()
+	m.retries = append(m.retries, method)
+}
+
+func (m *recordedMetrics) ObserveDecodeFailure(method string) {
+	m.Lock()
+	defer m.Unlock()
+	m.failures = append(m.failures, method)
+}
+
+func TestMetricsMethodNames(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/bigmaps":
+			fmt.Fprint(w, `[12]`)
+		case "/v1/tokens/transfers":
+			fmt.Fprint(w, `[]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	m := &recordedMetrics{}
+	c := New("", WithBaseURL(ts.URL), WithMetrics(m))
+
+	// nested calls are attributed to the method called by the user
+	pointer, err := c.GetBigMapPointerForContractTokenMetadata("KT1")
+	assert.NoError(t, err)
+	assert.Equal(t, 12, pointer)
+
+	it := c.IterateTokenTransfers("KT1", "1", 10)
+	assert.False(t, it.Next(context.Background()))
+	assert.NoError(t, it.Err())
+
+	_, err = c.GetTransactionByTx("oo1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Equal(t, []string{
+		"GetBigMapPointerForContractTokenMetadata 200",
+		"IterateTokenTransfers 200",
+		"GetTransactionByTx 404",
+	}, m.requests)
+}
+
+func TestMetricsRetriesAndDecodeFailures(t *testing.T) {
+	attempt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt++
+		if attempt == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `"level"`)
+	}))
+	defer ts.Close()
+
+	m := &recordedMetrics{}
+	c := New("",
+		WithBaseURL(ts.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		WithMetrics(m),
+	)
+
+	_, err := c.GetLevelByTime(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC))
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"GetLevelByTime 429", "GetLevelByTime 200"}, m.requests)
+	assert.Equal(t, []string{"GetLevelByTime"}, m.retries)
+	assert.Equal(t, []string{"GetLevelByTime"}, m.failures)
+}
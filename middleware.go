@@ -0,0 +1,39 @@
+package tzkt
+
+import "net/http"
+
+// Doer sends an http request and returns its response, like *http.Client
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc is a function used as a Doer
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer sending the api requests, to observe or alter the
+// requests and their responses. Each attempt of a request goes through the
+// middlewares with its own copy of the request, which they may modify.
+type Middleware func(next Doer) Doer
+
+// WithMiddleware adds middlewares around the requests sent by the client. The
+// first middleware is the outermost one, it sees the requests first and the
+// responses last.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *TZKT) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// doer returns the http client of c wrapped in its middlewares
+func (c *TZKT) doer() Doer {
+	var d Doer = c.client
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		d = c.middlewares[i](d)
+	}
+
+	return d
+}
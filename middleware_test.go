@@ -0,0 +1,96 @@
+package tzkt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%q", r.Header.Get("X-Trace"))
+	}))
+	defer ts.Close()
+
+	tag := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Trace", req.Header.Get("X-Trace")+name)
+				return next.Do(req)
+			})
+		}
+	}
+
+	c := New("", WithBaseURL(ts.URL), WithMiddleware(tag("a"), tag("b")), WithMiddleware(tag("c")))
+
+	req, err := http.NewRequest("GET", ts.URL, nil)
+	assert.NoError(t, err)
+
+	var trace string
+	assert.NoError(t, c.request(req, &trace))
+	assert.Equal(t, "abc", trace)
+}
+
+func TestMiddlewareRetryAttempts(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "key", r.Header.Get("X-Api-Key"))
+		fmt.Fprint(w, "3")
+	}))
+	defer ts.Close()
+
+	var attempts int32
+	apiKey := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "GetTokenTransfersCount", Method(req.Context()))
+			assert.Equal(t, int(atomic.AddInt32(&attempts, 1)), Attempt(req.Context()))
+			req.Header.Set("X-Api-Key", "key")
+			return next.Do(req)
+		})
+	}
+
+	c := New("",
+		WithBaseURL(ts.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		WithMiddleware(apiKey),
+	)
+
+	count, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
+
+func TestMiddlewareRewriteHost(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "12")
+	}))
+	defer ts.Close()
+
+	target, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	rewrite := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			return next.Do(req)
+		})
+	}
+
+	c := New("mainnet", WithMiddleware(rewrite))
+
+	level, err := c.GetLevelByTime(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), level)
+}
@@ -1,13 +1,11 @@
 package tzkt
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
-	"strconv"
-	"strings"
 	"time"
 )
 
@@ -36,6 +34,7 @@ type Transaction struct {
 }
 
 type DetailedTransaction struct {
+	Level     uint64                `json:"level"`
 	Block     string                `json:"block"`
 	Parameter *TransactionParameter `json:"parameter"`
 	Initiator *Account              `json:"initiator"`
@@ -48,76 +47,221 @@ type DetailedTransaction struct {
 	Status    string                `json:"status"`
 }
 
-// GetTransactionByTx gets transaction details from a specific Tx
-// confirmed => true; failed => false; pending => nil
-func (c *TZKT) GetTransactionStatusByTx(hash string) (*bool, error) {
-	u := url.URL{
-		Scheme: "https",
-		Host:   c.endpoint,
-		Path:   fmt.Sprintf("%s/%s/%s", "/v1/operations/transactions", hash, "status"),
+// OperationType is the type of an operation, as used by the type filters
+type OperationType string
+
+const (
+	OperationTypeTransaction OperationType = "transaction"
+	OperationTypeOrigination OperationType = "origination"
+	OperationTypeDelegation  OperationType = "delegation"
+	OperationTypeReveal      OperationType = "reveal"
+)
+
+// Operation is an operation of any type. Use a type switch to get the concrete
+// type, e.g. *TransactionOperation; the types without a dedicated struct are
+// decoded as *UnknownOperation.
+type Operation interface {
+	// Base returns the fields shared by the operations of every type
+	Base() OperationBase
+}
+
+// OperationBase are the fields shared by the operations of every type
+type OperationBase struct {
+	Type      OperationType `json:"type"`
+	ID        uint64        `json:"id"`
+	Level     uint64        `json:"level"`
+	Timestamp time.Time     `json:"timestamp"`
+	Block     string        `json:"block"`
+	Hash      string        `json:"hash"`
+	// Status is applied, failed, backtracked or skipped for manager operations
+	Status string `json:"status"`
+}
+
+func (o OperationBase) Base() OperationBase {
+	return o
+}
+
+// ManagerOperation are the fields shared by the operations signed by an account.
+// Fees are in mutez.
+type ManagerOperation struct {
+	Sender        Account `json:"sender"`
+	Counter       int64   `json:"counter"`
+	GasLimit      int64   `json:"gasLimit"`
+	GasUsed       int64   `json:"gasUsed"`
+	StorageLimit  int64   `json:"storageLimit"`
+	BakerFee      int64   `json:"bakerFee"`
+	StorageFee    int64   `json:"storageFee"`
+	AllocationFee int64   `json:"allocationFee"`
+}
+
+type TransactionOperation struct {
+	OperationBase
+	ManagerOperation
+
+	Initiator    *Account              `json:"initiator"`
+	Target       *Account              `json:"target"`
+	Amount       int64                 `json:"amount"`
+	Parameter    *TransactionParameter `json:"parameter"`
+	HasInternals bool                  `json:"hasInternals"`
+}
+
+type OriginationOperation struct {
+	OperationBase
+	ManagerOperation
+
+	Initiator          *Account `json:"initiator"`
+	OriginatedContract *Account `json:"originatedContract"`
+	ContractBalance    int64    `json:"contractBalance"`
+	ContractDelegate   *Account `json:"contractDelegate"`
+}
+
+type DelegationOperation struct {
+	OperationBase
+	ManagerOperation
+
+	Initiator    *Account `json:"initiator"`
+	PrevDelegate *Account `json:"prevDelegate"`
+	NewDelegate  *Account `json:"newDelegate"`
+	Amount       int64    `json:"amount"`
+}
+
+type RevealOperation struct {
+	OperationBase
+	ManagerOperation
+}
+
+// UnknownOperation is an operation of a type without a dedicated struct. Raw
+// is the whole json object of the operation.
+type UnknownOperation struct {
+	OperationBase
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// Operations is a list of operations decoded according to their type
+type Operations []Operation
+
+func (o *Operations) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
 	}
 
-	resp, err := c.client.Get(u.String())
-	if err != nil {
+	operations := make(Operations, 0, len(items))
+	for _, item := range items {
+		op, err := decodeOperation(item)
+		if err != nil {
+			return err
+		}
+		operations = append(operations, op)
+	}
+
+	*o = operations
+
+	return nil
+}
+
+func decodeOperation(data json.RawMessage) (Operation, error) {
+	var base OperationBase
+	if err := json.Unmarshal(data, &base); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("failed to get transaction status: %s", resp.Status)
+	var op Operation
+	switch base.Type {
+	case OperationTypeTransaction:
+		op = &TransactionOperation{}
+	case OperationTypeOrigination:
+		op = &OriginationOperation{}
+	case OperationTypeDelegation:
+		op = &DelegationOperation{}
+	case OperationTypeReveal:
+		op = &RevealOperation{}
+	default:
+		return &UnknownOperation{OperationBase: base, Raw: data}, nil
+	}
+
+	if err := json.Unmarshal(data, op); err != nil {
+		return nil, fmt.Errorf("%s operation %d: %w", base.Type, base.ID, err)
 	}
 
-	b, err := io.ReadAll(resp.Body)
+	return op, nil
+}
+
+// GetTransactionStatusByTx returns the status of a transaction
+// confirmed => true; failed => false; pending => nil
+func (c *TZKT) GetTransactionStatusByTx(hash string) (*bool, error) {
+	return c.GetTransactionStatusByTxWithContext(context.Background(), hash)
+}
+
+// GetTransactionStatusByTxWithContext is like GetTransactionStatusByTx but uses ctx for the api requests
+func (c *TZKT) GetTransactionStatusByTxWithContext(ctx context.Context, hash string) (*bool, error) {
+	ctx = withMethod(ctx, "GetTransactionStatusByTx")
+
+	u := c.apiURL(fmt.Sprintf("%s/%s/%s", "/v1/operations/transactions", hash, "status"), "")
+
+	var status *bool
 
+	// the status of a transaction only stays once its block is final
+	final := func(ctx context.Context, _ []byte) bool {
+		txs, err := c.GetTransactionByTxWithContext(ctx, hash)
+		return err == nil && len(txs) > 0 && c.isFinal(ctx, txs[0].Level)
+	}
+
+	req, err := http.NewRequestWithContext(immutableIf(ctx, final), "GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	status, err := strconv.ParseBool(string(b))
-	if err != nil {
-		return nil, nil
+	// an unknown transaction has no status yet
+	if err := c.request(req, &status); err != nil && !errors.Is(err, errNoContent) {
+		return nil, err
 	}
-	return &status, nil
+
+	return status, nil
 }
 
 // GetTransactionByTx gets transaction details from a specific Tx
 func (c *TZKT) GetTransactionByTx(hash string) ([]DetailedTransaction, error) {
-	u := url.URL{
-		Scheme: "https",
-		Host:   c.endpoint,
-		Path:   fmt.Sprintf("%s/%s", "/v1/operations/transactions", hash),
-	}
+	return c.GetTransactionByTxWithContext(context.Background(), hash)
+}
+
+// GetTransactionByTxWithContext is like GetTransactionByTx but uses ctx for the api requests
+func (c *TZKT) GetTransactionByTxWithContext(ctx context.Context, hash string) ([]DetailedTransaction, error) {
+	ctx = withMethod(ctx, "GetTransactionByTx")
+
+	u := c.apiURL(fmt.Sprintf("%s/%s", "/v1/operations/transactions", hash), "")
 
 	var transactionDetails []DetailedTransaction
 
-	resp, err := c.client.Get(u.String())
+	req, err := http.NewRequestWithContext(immutableIf(ctx, c.finalLevels), "GET", u.String(), nil)
 	if err != nil {
-		return transactionDetails, err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if err := json.NewDecoder(resp.Body).Decode(&transactionDetails); err != nil {
-		return transactionDetails, err
+	if err := c.request(req, &transactionDetails); err != nil {
+		return nil, err
 	}
 
 	return transactionDetails, nil
 }
 
 func (c *TZKT) GetTransaction(id uint64) (Transaction, error) {
-	v := url.Values{
-		"id": []string{fmt.Sprintf("%d", id)},
-	}
+	return c.GetTransactionWithContext(context.Background(), id)
+}
 
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/operations/transactions",
-		RawQuery: v.Encode(),
-	}
+// GetTransactionWithContext is like GetTransaction but uses ctx for the api requests
+func (c *TZKT) GetTransactionWithContext(ctx context.Context, id uint64) (Transaction, error) {
+	ctx = withMethod(ctx, "GetTransaction")
+
+	q := NewQuery().
+		Set("id", id)
+
+	u := c.apiURL("/v1/operations/transactions", q.Encode())
 
 	var txs []Transaction
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(immutableIf(ctx, c.finalLevels), "GET", u.String(), nil)
 	if err != nil {
 		return Transaction{}, err
 	}
@@ -126,36 +270,34 @@ func (c *TZKT) GetTransaction(id uint64) (Transaction, error) {
 	}
 
 	if len(txs) == 0 {
-		return Transaction{}, fmt.Errorf("transaction not found")
+		return Transaction{}, fmt.Errorf("transaction %w", ErrNotFound)
 	}
 	return txs[0], nil
 }
 
 func (c *TZKT) GetTransactions(contracts []string, entrypoints []string, lastTime *time.Time, offset, limit int) ([]Transaction, error) {
-	target := strings.Join(contracts, ",")
-	entrypoint := strings.Join(entrypoints, ",")
-	v := url.Values{
-		"target.in":     []string{target},
-		"entrypoint.in": []string{entrypoint},
-		"offset":        []string{fmt.Sprintf("%d", offset)},
-		"limit":         []string{fmt.Sprintf("%d", limit)},
-	}
+	return c.GetTransactionsWithContext(context.Background(), contracts, entrypoints, lastTime, offset, limit)
+}
+
+// GetTransactionsWithContext is like GetTransactions but uses ctx for the api requests
+func (c *TZKT) GetTransactionsWithContext(ctx context.Context, contracts []string, entrypoints []string, lastTime *time.Time, offset, limit int) ([]Transaction, error) {
+	ctx = withMethod(ctx, "GetTransactions")
+
+	q := NewQuery().
+		In("target", contracts).
+		In("entrypoint", entrypoints).
+		Offset(offset).
+		Limit(limit)
 
-	rawQuery := v.Encode()
 	if lastTime != nil {
-		rawQuery += "&timestamp.gt=" + lastTime.UTC().Format(time.RFC3339)
+		q.Gt("timestamp", *lastTime)
 	}
 
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/operations/transactions",
-		RawQuery: rawQuery,
-	}
+	u := c.apiURL("/v1/operations/transactions", q.Encode())
 
 	var txs []Transaction
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -165,3 +307,17 @@ func (c *TZKT) GetTransactions(contracts []string, entrypoints []string, lastTim
 
 	return txs, nil
 }
+
+// IterateTransactions returns an Iterator over all the transactions calling
+// the entrypoints of the contracts after lastTime
+func (c *TZKT) IterateTransactions(contracts []string, entrypoints []string, lastTime *time.Time, pageSize int) *Iterator[Transaction] {
+	q := NewQuery().
+		In("target", contracts).
+		In("entrypoint", entrypoints)
+
+	if lastTime != nil {
+		q.Gt("timestamp", *lastTime)
+	}
+
+	return newListIterator(c, "IterateTransactions", "/v1/operations/transactions", q, pageSize, func(t Transaction) uint64 { return t.ID })
+}
@@ -0,0 +1,88 @@
+package tzkt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTransactionStatusByTxStub(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/operations/transactions/confirmed/status":
+			fmt.Fprint(w, "true")
+		case "/v1/operations/transactions/failed/status":
+			fmt.Fprint(w, "false")
+		case "/v1/operations/transactions/pending/status":
+			fmt.Fprint(w, "null")
+		case "/v1/operations/transactions/unknown/status":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL))
+
+	status, err := tc.GetTransactionStatusByTx("confirmed")
+	assert.NoError(t, err)
+	assert.True(t, *status)
+
+	status, err = tc.GetTransactionStatusByTx("failed")
+	assert.NoError(t, err)
+	assert.False(t, *status)
+
+	status, err = tc.GetTransactionStatusByTx("pending")
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	status, err = tc.GetTransactionStatusByTx("unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	_, err = tc.GetTransactionStatusByTx("broken")
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+}
+
+func TestGetTransactionByTxStub(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/operations/transactions/ooJe9soP53x4dSBZR2mkEi1h3oQDCk5WZLaDBTVB3YzouC7dacQ":
+			fmt.Fprint(w, `[{"id":251825029644288,"hash":"ooJe9soP53x4dSBZR2mkEi1h3oQDCk5WZLaDBTVB3YzouC7dacQ","status":"applied","amount":0,"sender":{"address":"tz1QnNR17RHvXxDKHQEdRaAxrGL9hGysVcqT"},"target":{"alias":"Versum Items","address":"KT1LjmAdYQCLBjwv4S2oFkEzyHVkomAf5MrW"},"parameter":{"entrypoint":"transfer","value":[]}}]`)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "<html>bad gateway</html>")
+		}
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+
+	txs, err := tc.GetTransactionByTx("ooJe9soP53x4dSBZR2mkEi1h3oQDCk5WZLaDBTVB3YzouC7dacQ")
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, "applied", txs[0].Status)
+	assert.Equal(t, "transfer", txs[0].Parameter.EntryPoint)
+	assert.Equal(t, "Versum Items", txs[0].Target.Alias)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// an error page is reported instead of being decoded
+	_, err = tc.GetTransactionByTx("unknown")
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+}
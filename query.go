@@ -0,0 +1,231 @@
+package tzkt
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Query builds the query string of the TzKT API with its filter modes, e.g.
+//
+//	NewQuery().Eq("token.contract", contract).Gt("lastTime", t).SortAsc("id").Limit(100)
+//
+// Values are formatted by kind: times as RFC3339 in UTC, big integers and
+// TokenID in base 10 and slices as comma separated lists.
+type Query struct {
+	params map[string]string
+}
+
+// NewQuery returns an empty Query
+func NewQuery() *Query {
+	return &Query{params: map[string]string{}}
+}
+
+// Clone returns a copy of the query which can be modified independently
+func (q *Query) Clone() *Query {
+	c := NewQuery()
+	for k, v := range q.params {
+		c.params[k] = v
+	}
+
+	return c
+}
+
+// Set sets a parameter as is, replacing any previous value
+func (q *Query) Set(key string, value interface{}) *Query {
+	q.params[key] = formatQueryValue(value)
+	return q
+}
+
+// Get returns the value of a parameter
+func (q *Query) Get(key string) string {
+	return q.params[key]
+}
+
+// Del removes a parameter
+func (q *Query) Del(key string) *Query {
+	delete(q.params, key)
+	return q
+}
+
+// Eq filters items where field is equal to value
+func (q *Query) Eq(field string, value interface{}) *Query {
+	return q.Set(field+".eq", value)
+}
+
+// Ne filters items where field is not equal to value
+func (q *Query) Ne(field string, value interface{}) *Query {
+	return q.Set(field+".ne", value)
+}
+
+// Gt filters items where field is greater than value
+func (q *Query) Gt(field string, value interface{}) *Query {
+	return q.Set(field+".gt", value)
+}
+
+// Ge filters items where field is greater than or equal to value
+func (q *Query) Ge(field string, value interface{}) *Query {
+	return q.Set(field+".ge", value)
+}
+
+// Lt filters items where field is less than value
+func (q *Query) Lt(field string, value interface{}) *Query {
+	return q.Set(field+".lt", value)
+}
+
+// Le filters items where field is less than or equal to value
+func (q *Query) Le(field string, value interface{}) *Query {
+	return q.Set(field+".le", value)
+}
+
+// In filters items where field is one of the values
+func (q *Query) In(field string, values ...interface{}) *Query {
+	return q.Set(field+".in", values)
+}
+
+// Ni filters items where field is none of the values
+func (q *Query) Ni(field string, values ...interface{}) *Query {
+	return q.Set(field+".ni", values)
+}
+
+// As filters items where field matches the pattern, with * as a wildcard
+func (q *Query) As(field, pattern string) *Query {
+	return q.Set(field+".as", pattern)
+}
+
+// Un filters items where field does not match the pattern, with * as a wildcard
+func (q *Query) Un(field, pattern string) *Query {
+	return q.Set(field+".un", pattern)
+}
+
+// Null filters items where field is null, or not null when isNull is false
+func (q *Query) Null(field string, isNull bool) *Query {
+	return q.Set(field+".null", isNull)
+}
+
+// SortAsc sorts the items by field in ascending order
+func (q *Query) SortAsc(field string) *Query {
+	delete(q.params, "sort.desc")
+	return q.Set("sort.asc", field)
+}
+
+// SortDesc sorts the items by field in descending order
+func (q *Query) SortDesc(field string) *Query {
+	delete(q.params, "sort.asc")
+	return q.Set("sort.desc", field)
+}
+
+// Select returns only the given fields of the items as objects.
+// A field can be renamed with "field as name".
+func (q *Query) Select(fields ...string) *Query {
+	delete(q.params, "select.values")
+	return q.Set("select", fields)
+}
+
+// SelectValues returns only the given fields of the items as arrays of values
+func (q *Query) SelectValues(fields ...string) *Query {
+	delete(q.params, "select")
+	return q.Set("select.values", fields)
+}
+
+// Limit sets the maximum number of items returned
+func (q *Query) Limit(limit int) *Query {
+	return q.Set("limit", limit)
+}
+
+// Offset skips the given number of items
+func (q *Query) Offset(offset int) *Query {
+	q.clearOffset()
+	return q.Set("offset", offset)
+}
+
+// OffsetCursor returns the items after the one with the given id
+func (q *Query) OffsetCursor(id uint64) *Query {
+	q.clearOffset()
+	return q.Set("offset.cr", id)
+}
+
+// OffsetPage returns the given page of items, the page size being the limit
+func (q *Query) OffsetPage(page int) *Query {
+	q.clearOffset()
+	return q.Set("offset.pg", page)
+}
+
+func (q *Query) clearOffset() {
+	delete(q.params, "offset")
+	delete(q.params, "offset.cr")
+	delete(q.params, "offset.pg")
+}
+
+// Encode returns the query string sorted by key. Colons and commas are kept
+// as is to make the times and lists readable.
+func (q *Query) Encode() string {
+	if q == nil {
+		return ""
+	}
+
+	keys := make([]string, 0, len(q.params))
+	for k := range q.params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(queryEscape(k))
+		b.WriteByte('=')
+		b.WriteString(queryEscape(q.params[k]))
+	}
+
+	return b.String()
+}
+
+func (q *Query) String() string {
+	return q.Encode()
+}
+
+var queryUnescaper = strings.NewReplacer("%3A", ":", "%2C", ",")
+
+func queryEscape(s string) string {
+	return queryUnescaper.Replace(url.QueryEscape(s))
+}
+
+// formatQueryValue formats a value the way the TzKT API expects it
+func formatQueryValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case big.Int:
+		return v.String()
+	case *big.Int:
+		return v.String()
+	case TokenID:
+		return v.String()
+	case *TokenID:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		values := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, formatQueryValue(rv.Index(i).Interface()))
+		}
+
+		return strings.Join(values, ",")
+	}
+
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,56 @@
+package tzkt
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryEncode(t *testing.T) {
+	at := time.Date(2022, 5, 16, 19, 9, 29, 0, time.FixedZone("UTC+2", 2*3600))
+
+	q := NewQuery().
+		Set("account", "tz1RBi5DCVBYh1EGrcoJszkte1hDjrFfXm5C").
+		Gt("lastTime", at).
+		Ge("balance", 0).
+		In("token.contract", []string{"KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi", "KT1LjmAdYQCLBjwv4S2oFkEzyHVkomAf5MrW"}).
+		Ni("action", "add_key", "allocate").
+		Null("metadata", false).
+		SortDesc("lastLevel").
+		Select("account.address as address", "balance").
+		Limit(10)
+
+	assert.Equal(t, "account=tz1RBi5DCVBYh1EGrcoJszkte1hDjrFfXm5C"+
+		"&action.ni=add_key,allocate"+
+		"&balance.ge=0"+
+		"&lastTime.gt=2022-05-16T17:09:29Z"+
+		"&limit=10"+
+		"&metadata.null=false"+
+		"&select=account.address+as+address,balance"+
+		"&sort.desc=lastLevel"+
+		"&token.contract.in=KT1U6EHmNxJTkvaWJ4ThczG4FSDaHC21ssvi,KT1LjmAdYQCLBjwv4S2oFkEzyHVkomAf5MrW", q.Encode())
+}
+
+func TestQueryBigIntegers(t *testing.T) {
+	id, _ := new(big.Int).SetString("105353509316641797498497312618436889009736347208140239997663486800489418099672", 10)
+
+	q := NewQuery().
+		Eq("tokenId", id).
+		Ne("token.tokenId", TokenID{Int: *big.NewInt(42)}).
+		In("level", []uint64{1, 2, 3})
+
+	assert.Equal(t, id.String(), q.Get("tokenId.eq"))
+	assert.Equal(t, "42", q.Get("token.tokenId.ne"))
+	assert.Equal(t, "1,2,3", q.Get("level.in"))
+}
+
+func TestQueryExclusiveOptions(t *testing.T) {
+	q := NewQuery().SortAsc("id").Offset(10).Select("id")
+	c := q.Clone().SortDesc("level").OffsetCursor(42).SelectValues("id", "level")
+
+	assert.Equal(t, "offset=10&select=id&sort.asc=id", q.Encode())
+	assert.Equal(t, "offset.cr=42&select.values=id,level&sort.desc=level", c.Encode())
+	assert.Equal(t, "offset.pg=2", NewQuery().Offset(1).OffsetPage(2).Encode())
+}
@@ -0,0 +1,92 @@
+package tzkt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithRateLimit throttles the client to requestsPerSecond requests with bursts
+// of up to burst requests. The limit is shared by every goroutine using the
+// client, so concurrent callers wait for their turn instead of hitting 429.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *TZKT) {
+		c.limiter = newRateLimiter(requestsPerSecond, burst)
+	}
+}
+
+// rateLimiter is a token bucket. Callers reserve a token up front and sleep
+// until the reservation becomes due, which keeps the waiting order fair.
+type rateLimiter struct {
+	sync.Mutex
+
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token from the bucket and returns how long the caller has
+// to wait before using it
+func (l *rateLimiter) reserve() time.Duration {
+	l.Lock()
+	defer l.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel gives back a token that was reserved but not used
+func (l *rateLimiter) cancel() {
+	l.Lock()
+	defer l.Unlock()
+
+	l.tokens++
+}
+
+// wait blocks until the caller is allowed to send a request
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	delay := l.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
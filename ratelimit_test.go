@@ -0,0 +1,60 @@
+package tzkt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	l := newRateLimiter(20, 2)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, l.wait(context.Background()))
+	}
+
+	// the burst is free, the next three requests wait 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 140*time.Millisecond)
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	l := newRateLimiter(1, 1)
+	assert.NoError(t, l.wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, l.wait(ctx), context.DeadlineExceeded)
+}
+
+func TestRequestRateLimitConcurrent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "1")
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL), WithRateLimit(50, 1))
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// distinct times, identical requests would be coalesced
+			_, err := tc.GetLevelByTime(time.Now().Add(-time.Duration(i) * time.Hour))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
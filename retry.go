@@ -0,0 +1,79 @@
+package tzkt
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests that failed with a 429, a 5xx or a
+// transient network error are retried. Other 4xx responses are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	// A value lower than 2 disables retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry. It doubles on every
+	// following retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the exponential backoff. It does not cap a delay
+	// requested by the server through the Retry-After header.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is a reasonable policy for the public TzKT API
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// WithRetryPolicy enables automatic retries for every api call
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *TZKT) {
+		c.retry = policy
+	}
+}
+
+// backoff returns how long to wait before the given retry (starting from 1).
+// A random jitter of up to half of the delay is applied, and the delay is never
+// shorter than the one requested by the server.
+func (p RetryPolicy) backoff(retry int, retryAfter time.Duration) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if delay > 0 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+
+	if retryAfter > delay {
+		return retryAfter
+	}
+
+	return delay
+}
+
+// parseRetryAfter reads the Retry-After header which is either a number of
+// seconds or an http date
+func parseRetryAfter(header http.Header) time.Duration {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
@@ -0,0 +1,87 @@
+package tzkt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	d := p.backoff(1, 0)
+	assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+	assert.LessOrEqual(t, d, 100*time.Millisecond)
+
+	d = p.backoff(10, 0)
+	assert.GreaterOrEqual(t, d, 150*time.Millisecond)
+	assert.LessOrEqual(t, d, 300*time.Millisecond)
+
+	assert.Equal(t, 2*time.Second, p.backoff(1, 2*time.Second))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 3*time.Second, parseRetryAfter(http.Header{"Retry-After": []string{"3"}}))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(http.Header{}))
+
+	d := parseRetryAfter(http.Header{"Retry-After": []string{time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)}})
+	assert.Greater(t, d, 50*time.Second)
+}
+
+func TestRequestRetry(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			fmt.Fprint(w, "42")
+		}
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	level, err := tc.GetLevelByTime(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), level)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRequestRetryExhausted(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+
+	_, err := tc.GetLevelByTime(time.Now())
+	assert.ErrorIs(t, err, ErrTooManyRequest)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestRequestNoRetryOnBadRequest(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	tc := New("", WithBaseURL(ts.URL), WithRetryPolicy(DefaultRetryPolicy))
+
+	_, err := tc.GetLevelByTime(time.Now())
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,70 @@
+package tzkt
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// flight is a request in progress whose response is shared by all its callers
+type flight struct {
+	done chan struct{}
+	body []byte
+	err  error
+
+	// waiters is the number of callers waiting for the caller making the
+	// request
+	waiters int
+}
+
+// flightGroup coalesces identical concurrent requests so that only one of them
+// reaches the api. The zero value is ready to use.
+type flightGroup struct {
+	sync.Mutex
+
+	flights map[string]*flight
+}
+
+// do calls fn for the key unless a call for the same key is in progress, in
+// which case it waits for that call and returns its result instead
+func (g *flightGroup) do(ctx context.Context, key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.Lock()
+	if f, ok := g.flights[key]; ok {
+		f.waiters++
+		g.Unlock()
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		// the caller which made the request gave up, which does not apply to us
+		if isContextError(f.err) && ctx.Err() == nil {
+			return g.do(ctx, key, fn)
+		}
+
+		return f.body, f.err
+	}
+
+	if g.flights == nil {
+		g.flights = map[string]*flight{}
+	}
+
+	f := &flight{done: make(chan struct{})}
+	g.flights[key] = f
+	g.Unlock()
+
+	f.body, f.err = fn()
+
+	g.Lock()
+	delete(g.flights, key)
+	g.Unlock()
+	close(f.done)
+
+	return f.body, f.err
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
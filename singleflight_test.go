@@ -0,0 +1,131 @@
+package tzkt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waiting returns the number of callers waiting for the requests in progress
+func (g *flightGroup) waiting() int {
+	g.Lock()
+	defer g.Unlock()
+
+	n := 0
+	for _, f := range g.flights {
+		n += f.waiters
+	}
+
+	return n
+}
+
+func TestRequestDeduplication(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		fmt.Fprint(w, `[{"contract":{"address":"KT1"},"tokenId":"1","standard":"fa2"}]`)
+	}))
+	defer ts.Close()
+
+	c := New("", WithBaseURL(ts.URL))
+
+	var wg sync.WaitGroup
+	tokens := make([]Token, 5)
+	errs := make([]error, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], errs[i] = c.GetContractToken("KT1", "1")
+		}(i)
+	}
+
+	// let all the callers join the request before it completes
+	assert.Eventually(t, func() bool { return c.flights.waiting() == len(tokens)-1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for i := range tokens {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, "KT1", tokens[i].Contract.Address)
+	}
+
+	// later requests are not coalesced with completed ones
+	_, err := c.GetContractToken("KT1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestRequestDeduplicationError(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	c := New("", WithBaseURL(ts.URL))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = c.GetContractToken("KT1", "1")
+		}(i)
+	}
+
+	assert.Eventually(t, func() bool { return c.flights.waiting() == len(errs)-1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, err := range errs {
+		assert.ErrorIs(t, err, ErrBadRequest)
+	}
+}
+
+func TestFlightGroupCanceledLeader(t *testing.T) {
+	var g flightGroup
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	leader := make(chan error)
+	go func() {
+		_, err := g.do(ctx, "k", func() ([]byte, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		leader <- err
+	}()
+
+	<-started
+	follower := make(chan []byte)
+	go func() {
+		body, err := g.do(context.Background(), "k", func() ([]byte, error) {
+			return []byte("ok"), nil
+		})
+		assert.NoError(t, err)
+		follower <- body
+	}()
+
+	assert.Eventually(t, func() bool { return g.waiting() == 1 }, time.Second, time.Millisecond)
+	cancel()
+
+	assert.ErrorIs(t, <-leader, context.Canceled)
+	assert.Equal(t, []byte("ok"), <-follower)
+}
@@ -0,0 +1,121 @@
+package tzkt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+type streamKey struct{}
+
+// withStream makes the successful responses to the requests sent with the
+// returned context read by decode instead of being buffered
+func withStream(ctx context.Context, decode func(io.Reader) error) context.Context {
+	return context.WithValue(ctx, streamKey{}, decode)
+}
+
+func streamDecoder(ctx context.Context) func(io.Reader) error {
+	decode, _ := ctx.Value(streamKey{}).(func(io.Reader) error)
+	return decode
+}
+
+// Streamed reports whether the response to a request is decoded while it is
+// read, given the context of the request. Middlewares should not buffer the
+// body of such responses.
+func Streamed(ctx context.Context) bool {
+	return streamDecoder(ctx) != nil
+}
+
+// SendTo returns a callback for the Stream methods which sends the items to ch,
+// giving up when ctx is done
+func SendTo[T any](ctx context.Context, ch chan<- T) func(T) error {
+	return func(item T) error {
+		select {
+		case ch <- item:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// streamArray sends the request and passes the items of the json array in the
+// response to fn as they are decoded, keeping a single item in memory. The
+// streamed requests skip the cache, and are not retried once the response
+// started to be decoded since fn may have received items already. An error
+// returned by fn stops the decoding and is returned as is.
+func streamArray[T any](c *TZKT, req *http.Request, fn func(T) error) error {
+	decode := func(r io.Reader) error {
+		dec := json.NewDecoder(r)
+
+		err := decodeArray(dec, fn)
+		var decodeErr *streamDecodeError
+		if errors.As(err, &decodeErr) {
+			if c.metrics != nil {
+				c.metrics.ObserveDecodeFailure(methodName(req.Context()))
+			}
+
+			c.log(req.Context(), slog.LevelError, "tzkt response can not be decoded",
+				slog.String("url", req.URL.String()),
+				slog.String("error", decodeErr.err.Error()),
+				slog.Int64("offset", dec.InputOffset()),
+			)
+
+			return decodeErr.err
+		}
+
+		return err
+	}
+
+	_, err := c.send(req.WithContext(withStream(req.Context(), decode)))
+
+	return err
+}
+
+// streamDecodeError tells decoding failures apart from the errors of callbacks
+type streamDecodeError struct {
+	err error
+}
+
+func (e *streamDecodeError) Error() string {
+	return e.err.Error()
+}
+
+func decodeArray[T any](dec *json.Decoder, fn func(T) error) error {
+	t, err := dec.Token()
+	if err == io.EOF {
+		// an empty body has no items
+		return nil
+	}
+	if err != nil {
+		return &streamDecodeError{err}
+	}
+
+	if t == nil {
+		return nil
+	}
+	if t != json.Delim('[') {
+		return &streamDecodeError{fmt.Errorf("expected a json array, got %v", t)}
+	}
+
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return &streamDecodeError{err}
+		}
+
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return &streamDecodeError{err}
+	}
+
+	return nil
+}
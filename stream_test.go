@@ -0,0 +1,149 @@
+package tzkt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamTokenTransfersByLevel(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/tokens/transfers", r.URL.Path)
+		assert.Equal(t, "10", r.URL.Query().Get("level.eq"))
+		fmt.Fprint(w, `[{"id":1,"level":10,"amount":"1"}, {"id":2,"level":10,"amount":"3"}]`)
+	}))
+	defer ts.Close()
+
+	c := New("", WithBaseURL(ts.URL))
+
+	var transfers []TokenTransfer
+	err := c.StreamTokenTransfersByLevel("10", 0, 0, func(t TokenTransfer) error {
+		transfers = append(transfers, t)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 2)
+	assert.Equal(t, uint64(2), transfers[1].ID)
+	assert.Equal(t, "3", *transfers[1].Amount)
+
+	// a callback error stops the stream
+	stop := errors.New("stop")
+	calls := 0
+	err = c.StreamTokenTransfersByLevel("10", 0, 0, func(t TokenTransfer) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestStreamEmpty(t *testing.T) {
+	for _, body := range []string{``, `[]`, `null`} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		}))
+
+		c := New("", WithBaseURL(ts.URL))
+		err := c.StreamTokenMetadataBigmapUpdatesByLevel("10", 0, 0, func(BigmapUpdate) error {
+			t.Fatal("unexpected item")
+			return nil
+		})
+		assert.NoError(t, err, body)
+
+		ts.Close()
+	}
+}
+
+func TestStreamDecodeFailure(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		fmt.Fprint(w, `[{"id":1,"level":10},{"id":"two"`)
+	}))
+	defer ts.Close()
+
+	m := &recordedMetrics{}
+	c := New("",
+		WithBaseURL(ts.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		WithMetrics(m),
+	)
+
+	var ids []uint64
+	err := c.StreamTokenTransfersByLevel("10", 0, 0, func(t TokenTransfer) error {
+		ids = append(ids, t.ID)
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []uint64{1}, ids)
+
+	// not retried since an item was handed out already
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, []string{"StreamTokenTransfersByLevel"}, m.failures)
+}
+
+func TestStreamRetryBeforeDecoding(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `[{"id":1}]`)
+	}))
+	defer ts.Close()
+
+	c := New("",
+		WithBaseURL(ts.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		WithCache(NewLRUCache(10), time.Minute),
+	)
+
+	count := 0
+	err := c.StreamTokenMetadataBigmapUpdatesByLevel("10", 0, 0, func(BigmapUpdate) error {
+		count++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// streamed responses are not cached
+	assert.Equal(t, 0, c.cache.(*LRUCache).Len())
+}
+
+func TestSendTo(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id":1},{"id":2},{"id":3}]`)
+	}))
+	defer ts.Close()
+
+	c := New("", WithBaseURL(ts.URL))
+
+	ch := make(chan TokenTransfer)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		errs <- c.StreamTokenTransfersByLevel("10", 0, 0, SendTo(context.Background(), ch))
+	}()
+
+	var ids []uint64
+	for transfer := range ch {
+		ids = append(ids, transfer.ID)
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, []uint64{1, 2, 3}, ids)
+
+	// a canceled context unblocks the sender
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := c.StreamTokenTransfersByLevelWithContext(context.Background(), "10", 0, 0, SendTo(ctx, make(chan TokenTransfer)))
+	assert.ErrorIs(t, err, context.Canceled)
+}
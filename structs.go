@@ -24,7 +24,7 @@ type NullableInt int64
 func (t *NullableInt) UnmarshalJSON(data []byte) error {
 	var num int64
 
-	err := json.Unmarshal(data, &num)
+	err := json.Unmarshal(bytes.Trim(data, `"`), &num)
 	if err != nil {
 		*t = NullableInt(-1)
 
@@ -19,6 +19,31 @@ func TestUnmarshalFlexInt64(t *testing.T) {
 	assert.Equal(t, int64(1234), int64(i))
 }
 
+func TestUnmarshalNullableInt(t *testing.T) {
+	var i NullableInt
+
+	err := json.Unmarshal([]byte("12"), &i)
+	assert.NoError(t, err)
+	assert.Equal(t, NullableInt(12), i)
+
+	err = json.Unmarshal([]byte(`"34"`), &i)
+	assert.NoError(t, err)
+	assert.Equal(t, NullableInt(34), i)
+
+	// amounts beyond int64, e.g. huge supplies, are -1
+	err = json.Unmarshal([]byte(`"100000000000000000000000000000"`), &i)
+	assert.NoError(t, err)
+	assert.Equal(t, NullableInt(-1), i)
+
+	// as used by the ",string" balances of the api
+	var balance struct {
+		Balance NullableInt `json:"balance,string"`
+	}
+	err = json.Unmarshal([]byte(`{"balance":"56"}`), &balance)
+	assert.NoError(t, err)
+	assert.Equal(t, NullableInt(56), balance.Balance)
+}
+
 func TestUnmarshalFlexBool(t *testing.T) {
 	var b FlexBool
 
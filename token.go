@@ -1,9 +1,9 @@
 package tzkt
 
 import (
+	"context"
 	"fmt"
 	"net/http"
-	"net/url"
 	"time"
 )
 
@@ -31,6 +31,7 @@ type Token struct {
 }
 
 type OwnedToken struct {
+	ID        uint64      `json:"id"`
 	Token     Token       `json:"token"`
 	Balance   NullableInt `json:"balance,string"`
 	FirstTime time.Time   `json:"firstTime"`
@@ -55,6 +56,7 @@ type TokenMetadata struct {
 }
 
 type TokenTransfer struct {
+	ID            uint64    `json:"id"`
 	Timestamp     time.Time `json:"timestamp"`
 	Level         uint64    `json:"level"`
 	TransactionID uint64    `json:"transactionId"`
@@ -64,6 +66,15 @@ type TokenTransfer struct {
 	Token         *Token    `json:"token"`
 }
 
+// HistoricalTokenBalance is the balance of a token held by an account at a
+// past block level
+type HistoricalTokenBalance struct {
+	ID      uint64      `json:"id"`
+	Account Account     `json:"account"`
+	Token   Token       `json:"token"`
+	Balance NullableInt `json:"balance,string"`
+}
+
 type TokenOwner struct {
 	Address     string    `json:"address"`
 	Balance     int64     `json:"balance,string"`
@@ -73,23 +84,24 @@ type TokenOwner struct {
 
 // GetTokenBalanceOfOwner gets token balance of an owner
 func (c *TZKT) GetTokenBalanceOfOwner(contract, tokenID, owner string) (int64, error) {
-	v := url.Values{
-		"account":        []string{owner},
-		"token.contract": []string{contract},
-		"token.tokenId":  []string{tokenID},
-		"token.standard": []string{"fa2"},
-	}
+	return c.GetTokenBalanceOfOwnerWithContext(context.Background(), contract, tokenID, owner)
+}
 
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/tokens/balances/count",
-		RawQuery: v.Encode(),
-	}
+// GetTokenBalanceOfOwnerWithContext is like GetTokenBalanceOfOwner but uses ctx for the api requests
+func (c *TZKT) GetTokenBalanceOfOwnerWithContext(ctx context.Context, contract, tokenID, owner string) (int64, error) {
+	ctx = withMethod(ctx, "GetTokenBalanceOfOwner")
+
+	q := NewQuery().
+		Set("account", owner).
+		Set("token.contract", contract).
+		Set("token.tokenId", tokenID).
+		Set("token.standard", "fa2")
+
+	u := c.apiURL("/v1/tokens/balances/count", q.Encode())
 
 	var balance int64
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return balance, err
 	}
@@ -102,28 +114,28 @@ func (c *TZKT) GetTokenBalanceOfOwner(contract, tokenID, owner string) (int64, e
 
 // GetTokenOwners returns a list of TokenOwner for a specific token
 func (c *TZKT) GetTokenOwners(contract, tokenID string, limit int, lastTime time.Time) ([]TokenOwner, error) {
-	v := url.Values{
-		"token.contract": []string{contract},
-		"token.tokenId":  []string{tokenID},
-		"balance.gt":     []string{"0"},
-		"token.standard": []string{"fa2"},
-		"sort.asc":       []string{"lastLevel"},
-		"limit":          []string{fmt.Sprintf("%d", limit)},
-		"select":         []string{"account.address as address,balance,lastTime,token.totalSupply as totalSupply"},
-	}
+	return c.GetTokenOwnersWithContext(context.Background(), contract, tokenID, limit, lastTime)
+}
 
-	rawQuery := v.Encode() + "&lastTime.ge=" + lastTime.UTC().Format(time.RFC3339)
+// GetTokenOwnersWithContext is like GetTokenOwners but uses ctx for the api requests
+func (c *TZKT) GetTokenOwnersWithContext(ctx context.Context, contract, tokenID string, limit int, lastTime time.Time) ([]TokenOwner, error) {
+	ctx = withMethod(ctx, "GetTokenOwners")
 
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/tokens/balances",
-		RawQuery: rawQuery,
-	}
+	q := NewQuery().
+		Set("token.contract", contract).
+		Set("token.tokenId", tokenID).
+		Gt("balance", 0).
+		Set("token.standard", "fa2").
+		Ge("lastTime", lastTime).
+		SortAsc("lastLevel").
+		Limit(limit).
+		Select("account.address as address", "balance", "lastTime", "token.totalSupply as totalSupply")
+
+	u := c.apiURL("/v1/tokens/balances", q.Encode())
 
 	var owners []TokenOwner
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -136,25 +148,26 @@ func (c *TZKT) GetTokenOwners(contract, tokenID string, limit int, lastTime time
 
 // GetTokenBalanceAndLastTimeForOwner returns balance and last activity time of an owner for a specific token
 func (c *TZKT) GetTokenBalanceAndLastTimeForOwner(contract, tokenID, owner string) (int64, time.Time, error) {
-	v := url.Values{
-		"token.contract": []string{contract},
-		"token.tokenId":  []string{tokenID},
-		"balance.gt":     []string{"0"},
-		"account":        []string{owner},
-		"token.standard": []string{"fa2"},
-		"select":         []string{"lastTime,account.address as address,balance"},
-	}
+	return c.GetTokenBalanceAndLastTimeForOwnerWithContext(context.Background(), contract, tokenID, owner)
+}
 
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/tokens/balances",
-		RawQuery: v.Encode(),
-	}
+// GetTokenBalanceAndLastTimeForOwnerWithContext is like GetTokenBalanceAndLastTimeForOwner but uses ctx for the api requests
+func (c *TZKT) GetTokenBalanceAndLastTimeForOwnerWithContext(ctx context.Context, contract, tokenID, owner string) (int64, time.Time, error) {
+	ctx = withMethod(ctx, "GetTokenBalanceAndLastTimeForOwner")
+
+	q := NewQuery().
+		Set("token.contract", contract).
+		Set("token.tokenId", tokenID).
+		Gt("balance", 0).
+		Set("account", owner).
+		Set("token.standard", "fa2").
+		Select("lastTime", "account.address as address", "balance")
+
+	u := c.apiURL("/v1/tokens/balances", q.Encode())
 
 	var owners []TokenOwner
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return 0, time.Time{}, err
 	}
@@ -164,7 +177,7 @@ func (c *TZKT) GetTokenBalanceAndLastTimeForOwner(contract, tokenID, owner strin
 	}
 
 	if len(owners) == 0 {
-		return 0, time.Time{}, fmt.Errorf("token not found")
+		return 0, time.Time{}, fmt.Errorf("token %w", ErrNotFound)
 	}
 
 	if len(owners) > 1 {
@@ -176,25 +189,26 @@ func (c *TZKT) GetTokenBalanceAndLastTimeForOwner(contract, tokenID, owner strin
 
 // GetTokenLastActivityTime returns the timestamp of the last activity for a token
 func (c *TZKT) GetTokenLastActivityTime(contract, tokenID string) (time.Time, error) {
-	v := url.Values{
-		"token.contract": []string{contract},
-		"token.tokenId":  []string{tokenID},
-		"token.standard": []string{"fa2"},
-		"sort.desc":      []string{"timestamp"},
-		"limit":          []string{"1"},
-		"select":         []string{"timestamp"},
-	}
+	return c.GetTokenLastActivityTimeWithContext(context.Background(), contract, tokenID)
+}
 
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/tokens/transfers",
-		RawQuery: v.Encode(),
-	}
+// GetTokenLastActivityTimeWithContext is like GetTokenLastActivityTime but uses ctx for the api requests
+func (c *TZKT) GetTokenLastActivityTimeWithContext(ctx context.Context, contract, tokenID string) (time.Time, error) {
+	ctx = withMethod(ctx, "GetTokenLastActivityTime")
+
+	q := NewQuery().
+		Set("token.contract", contract).
+		Set("token.tokenId", tokenID).
+		Set("token.standard", "fa2").
+		SortDesc("timestamp").
+		Limit(1).
+		Select("timestamp")
+
+	u := c.apiURL("/v1/tokens/transfers", q.Encode())
 
 	var activityTime []time.Time
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -204,35 +218,36 @@ func (c *TZKT) GetTokenLastActivityTime(contract, tokenID string) (time.Time, er
 	}
 
 	if len(activityTime) == 0 {
-		return time.Time{}, fmt.Errorf("no activities for this token")
+		return time.Time{}, fmt.Errorf("no activities for this token: %w", ErrNotFound)
 	}
 
 	return activityTime[0], nil
 }
 
 func (c *TZKT) GetTokenTransfers(contract, tokenID string, limit int) ([]TokenTransfer, error) {
+	return c.GetTokenTransfersWithContext(context.Background(), contract, tokenID, limit)
+}
+
+// GetTokenTransfersWithContext is like GetTokenTransfers but uses ctx for the api requests
+func (c *TZKT) GetTokenTransfersWithContext(ctx context.Context, contract, tokenID string, limit int) ([]TokenTransfer, error) {
+	ctx = withMethod(ctx, "GetTokenTransfers")
+
 	if limit == 0 {
 		limit = 100
 	}
 
-	v := url.Values{
-		"token.contract": []string{contract},
-		"token.tokenId":  []string{tokenID},
-		"token.standard": []string{"fa2"},
-		"limit":          []string{fmt.Sprint(limit)},
-		"select":         []string{"timestamp,from,to,transactionId,level"},
-	}
+	q := NewQuery().
+		Set("token.contract", contract).
+		Set("token.tokenId", tokenID).
+		Set("token.standard", "fa2").
+		Limit(limit).
+		Select("timestamp", "from", "to", "transactionId", "level")
 
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/tokens/transfers",
-		RawQuery: v.Encode(),
-	}
+	u := c.apiURL("/v1/tokens/transfers", q.Encode())
 
 	var transfers []TokenTransfer
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -245,27 +260,18 @@ func (c *TZKT) GetTokenTransfers(contract, tokenID string, limit int) ([]TokenTr
 }
 
 func (c *TZKT) GetTokenTransfersByLevel(level string, offset, limit int) ([]TokenTransfer, error) {
-	if limit == 0 {
-		limit = 100
-	}
+	return c.GetTokenTransfersByLevelWithContext(context.Background(), level, offset, limit)
+}
 
-	v := url.Values{
-		"level.eq": []string{level},
-		"sort":     []string{"level"},
-		"offset":   []string{fmt.Sprint(offset)},
-		"limit":    []string{fmt.Sprint(limit)},
-	}
+// GetTokenTransfersByLevelWithContext is like GetTokenTransfersByLevel but uses ctx for the api requests
+func (c *TZKT) GetTokenTransfersByLevelWithContext(ctx context.Context, level string, offset, limit int) ([]TokenTransfer, error) {
+	ctx = withMethod(ctx, "GetTokenTransfersByLevel")
 
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/tokens/transfers",
-		RawQuery: v.Encode(),
-	}
+	u := c.apiURL("/v1/tokens/transfers", tokenTransfersByLevelQuery(level, offset, limit).Encode())
 
 	var transfers []TokenTransfer
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -277,23 +283,57 @@ func (c *TZKT) GetTokenTransfersByLevel(level string, offset, limit int) ([]Toke
 	return transfers, nil
 }
 
-func (c *TZKT) GetTokenTransfersCount(contract, tokenID string) (int, error) {
-	v := url.Values{
-		"token.contract": []string{contract},
-		"token.tokenId":  []string{tokenID},
-		"token.standard": []string{"fa2"},
+// StreamTokenTransfersByLevel is like GetTokenTransfersByLevel but passes the
+// transfers to fn one at a time as they are decoded, so that large pages are
+// never held in memory. It stops at the first error returned by fn.
+func (c *TZKT) StreamTokenTransfersByLevel(level string, offset, limit int, fn func(TokenTransfer) error) error {
+	return c.StreamTokenTransfersByLevelWithContext(context.Background(), level, offset, limit, fn)
+}
+
+// StreamTokenTransfersByLevelWithContext is like StreamTokenTransfersByLevel but uses ctx for the api requests
+func (c *TZKT) StreamTokenTransfersByLevelWithContext(ctx context.Context, level string, offset, limit int, fn func(TokenTransfer) error) error {
+	ctx = withMethod(ctx, "StreamTokenTransfersByLevel")
+
+	u := c.apiURL("/v1/tokens/transfers", tokenTransfersByLevelQuery(level, offset, limit).Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return err
 	}
 
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/tokens/transfers/count",
-		RawQuery: v.Encode(),
+	return streamArray(c, req, fn)
+}
+
+func tokenTransfersByLevelQuery(level string, offset, limit int) *Query {
+	if limit == 0 {
+		limit = 100
 	}
 
+	return NewQuery().
+		Eq("level", level).
+		SortAsc("level").
+		Offset(offset).
+		Limit(limit)
+}
+
+func (c *TZKT) GetTokenTransfersCount(contract, tokenID string) (int, error) {
+	return c.GetTokenTransfersCountWithContext(context.Background(), contract, tokenID)
+}
+
+// GetTokenTransfersCountWithContext is like GetTokenTransfersCount but uses ctx for the api requests
+func (c *TZKT) GetTokenTransfersCountWithContext(ctx context.Context, contract, tokenID string) (int, error) {
+	ctx = withMethod(ctx, "GetTokenTransfersCount")
+
+	q := NewQuery().
+		Set("token.contract", contract).
+		Set("token.tokenId", tokenID).
+		Set("token.standard", "fa2")
+
+	u := c.apiURL("/v1/tokens/transfers/count", q.Encode())
+
 	var count int
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return 0, err
 	}
@@ -308,29 +348,28 @@ func (c *TZKT) GetTokenTransfersCount(contract, tokenID string) (int, error) {
 // RetrieveTokens returns OwnedToken for a specific token. The OwnedToken object includes
 // both balance and token information
 func (c *TZKT) RetrieveTokens(owner string, lastTime time.Time, offset int) ([]OwnedToken, error) {
-	v := url.Values{
-		"account":        []string{owner},
-		"limit":          []string{"50"},
-		"offset":         []string{fmt.Sprintf("%d", offset)},
-		"balance.ge":     []string{"0"},
-		"token.standard": []string{"fa2"},
-		"sort.asc":       []string{"lastLevel"},
+	return c.RetrieveTokensWithContext(context.Background(), owner, lastTime, offset)
+}
+
+// RetrieveTokensWithContext is like RetrieveTokens but uses ctx for the api requests
+func (c *TZKT) RetrieveTokensWithContext(ctx context.Context, owner string, lastTime time.Time, offset int) ([]OwnedToken, error) {
+	ctx = withMethod(ctx, "RetrieveTokens")
+
+	q := NewQuery().
+		Set("account", owner).
+		Limit(50).
+		Offset(offset).
+		Ge("balance", 0).
+		Set("token.standard", "fa2").
+		Gt("lastTime", lastTime).
 		// NOTE: sorting over lastTime is not reliable in tzkt api. Use `lastLevel` instead
 		// For example: https://api.tzkt.io/v1/tokens/balances?account=tz2GoQHhadigAa56HnAXTGAYpYn8xUZsrG11&sort=lastTime&token.standard=fa2&balance.ge=0&lastTime.ge=2022-05-16T17:09:29Z
-	}
+		SortAsc("lastLevel")
 
-	// prevent QueryEscape for colons in time
-	rawQuery := v.Encode() + "&lastTime.gt=" + lastTime.UTC().Format(time.RFC3339)
-
-	u := url.URL{
-		Scheme:   "https",
-		Host:     c.endpoint,
-		Path:     "/v1/tokens/balances",
-		RawQuery: rawQuery,
-	}
+	u := c.apiURL("/v1/tokens/balances", q.Encode())
 	var ownedTokens []OwnedToken
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return ownedTokens, err
 	}
@@ -343,19 +382,22 @@ func (c *TZKT) RetrieveTokens(owner string, lastTime time.Time, offset int) ([]O
 }
 
 func (c *TZKT) GetContractToken(contract, tokenID string) (Token, error) {
-	u := url.URL{
-		Scheme: "https",
-		Host:   c.endpoint,
-		Path:   "/v1/tokens",
-		RawQuery: url.Values{
-			"contract": []string{contract},
-			"tokenId":  []string{tokenID},
-		}.Encode(),
-	}
+	return c.GetContractTokenWithContext(context.Background(), contract, tokenID)
+}
+
+// GetContractTokenWithContext is like GetContractToken but uses ctx for the api requests
+func (c *TZKT) GetContractTokenWithContext(ctx context.Context, contract, tokenID string) (Token, error) {
+	ctx = withMethod(ctx, "GetContractToken")
+
+	q := NewQuery().
+		Set("contract", contract).
+		Set("tokenId", tokenID)
+
+	u := c.apiURL("/v1/tokens", q.Encode())
 
 	var tokenResponse []Token
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return Token{}, err
 	}
@@ -365,8 +407,120 @@ func (c *TZKT) GetContractToken(contract, tokenID string) (Token, error) {
 	}
 
 	if len(tokenResponse) == 0 {
-		return Token{}, fmt.Errorf("token not found")
+		return Token{}, fmt.Errorf("token %w", ErrNotFound)
 	}
 
 	return tokenResponse[0], nil
 }
+
+// IterateTokenTransfers returns an Iterator over all the transfers of a token
+func (c *TZKT) IterateTokenTransfers(contract, tokenID string, pageSize int) *Iterator[TokenTransfer] {
+	q := NewQuery().
+		Set("token.contract", contract).
+		Set("token.tokenId", tokenID).
+		Set("token.standard", "fa2").
+		Select("id", "timestamp", "from", "to", "transactionId", "level")
+
+	return newListIterator(c, "IterateTokenTransfers", "/v1/tokens/transfers", q, pageSize, func(t TokenTransfer) uint64 { return t.ID })
+}
+
+// IterateTokenTransfersByLevel returns an Iterator over all the token transfers of a block level
+func (c *TZKT) IterateTokenTransfersByLevel(level string, pageSize int) *Iterator[TokenTransfer] {
+	q := NewQuery().
+		Eq("level", level)
+
+	return newListIterator(c, "IterateTokenTransfersByLevel", "/v1/tokens/transfers", q, pageSize, func(t TokenTransfer) uint64 { return t.ID })
+}
+
+// IterateOwnedTokens returns an Iterator over all the OwnedToken of an owner updated after lastTime.
+// It walks the same items as RetrieveTokens without the fixed page size.
+func (c *TZKT) IterateOwnedTokens(owner string, lastTime time.Time, pageSize int) *Iterator[OwnedToken] {
+	q := NewQuery().
+		Set("account", owner).
+		Ge("balance", 0).
+		Set("token.standard", "fa2").
+		Gt("lastTime", lastTime)
+
+	return newListIterator(c, "IterateOwnedTokens", "/v1/tokens/balances", q, pageSize, func(t OwnedToken) uint64 { return t.ID })
+}
+
+// errNoBalancesFilter is returned for the historical balances of every token
+// and owner, which are never worth reading
+var errNoBalancesFilter = fmt.Errorf("%w: an owner or a token contract is required", ErrBadRequest)
+
+// GetTokenBalancesAtLevel returns the token balances at the end of a block
+// level, filtered by token contract, token id and owner. An owner or a token
+// contract is required, the other filters match everything when empty, e.g.
+// all the tokens of an owner. Every page of balances is read.
+func (c *TZKT) GetTokenBalancesAtLevel(contract, tokenID, owner string, level uint64) ([]HistoricalTokenBalance, error) {
+	return c.GetTokenBalancesAtLevelWithContext(context.Background(), contract, tokenID, owner, level)
+}
+
+// GetTokenBalancesAtLevelWithContext is like GetTokenBalancesAtLevel but uses ctx for the api requests
+func (c *TZKT) GetTokenBalancesAtLevelWithContext(ctx context.Context, contract, tokenID, owner string, level uint64) ([]HistoricalTokenBalance, error) {
+	ctx = withMethod(ctx, "GetTokenBalancesAtLevel")
+
+	if contract == "" && owner == "" {
+		return nil, errNoBalancesFilter
+	}
+
+	// the balances at a final level never change, while tzkt answers a level
+	// still to come with the latest balances
+	if c.cache != nil && c.isFinal(ctx, level) {
+		ctx = immutable(ctx)
+	}
+
+	var balances []HistoricalTokenBalance
+
+	it := c.IterateTokenBalancesAtLevel(contract, tokenID, owner, level, 0)
+	for it.Next(ctx) {
+		balances = append(balances, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// GetTokenBalancesAtTime is like GetTokenBalancesAtLevel for the level of the
+// last block produced before the given time
+func (c *TZKT) GetTokenBalancesAtTime(contract, tokenID, owner string, at time.Time) ([]HistoricalTokenBalance, error) {
+	return c.GetTokenBalancesAtTimeWithContext(context.Background(), contract, tokenID, owner, at)
+}
+
+// GetTokenBalancesAtTimeWithContext is like GetTokenBalancesAtTime but uses ctx for the api requests
+func (c *TZKT) GetTokenBalancesAtTimeWithContext(ctx context.Context, contract, tokenID, owner string, at time.Time) ([]HistoricalTokenBalance, error) {
+	ctx = withMethod(ctx, "GetTokenBalancesAtTime")
+
+	level, err := c.GetLevelByTimeWithContext(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetTokenBalancesAtLevelWithContext(ctx, contract, tokenID, owner, level)
+}
+
+// IterateTokenBalancesAtLevel returns an Iterator over the token balances at the
+// end of a block level, filtered like GetTokenBalancesAtLevel
+func (c *TZKT) IterateTokenBalancesAtLevel(contract, tokenID, owner string, level uint64, pageSize int) *Iterator[HistoricalTokenBalance] {
+	if contract == "" && owner == "" {
+		return NewIterator(pageSize, func(b HistoricalTokenBalance) uint64 { return b.ID }, func(context.Context, uint64, int) ([]HistoricalTokenBalance, error) {
+			return nil, errNoBalancesFilter
+		})
+	}
+
+	q := NewQuery()
+
+	if contract != "" {
+		q.Set("token.contract", contract)
+	}
+	if tokenID != "" {
+		q.Set("token.tokenId", tokenID)
+	}
+	if owner != "" {
+		q.Set("account", owner)
+	}
+
+	return newListIterator(c, "IterateTokenBalancesAtLevel", fmt.Sprintf("/v1/tokens/historical_balances/%d", level), q, pageSize, func(b HistoricalTokenBalance) uint64 { return b.ID })
+}
@@ -0,0 +1,232 @@
+package tzktfake
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+)
+
+func (c *Client) GetAccount(address string) (tzkt.AccountInfo, error) {
+	return c.GetAccountWithContext(context.Background(), address)
+}
+
+// GetAccountWithContext returns the account of an address, or an empty account
+// like the api when it is unknown
+func (c *Client) GetAccountWithContext(ctx context.Context, address string) (tzkt.AccountInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return tzkt.AccountInfo{}, c.Err
+	}
+
+	for _, a := range c.Accounts {
+		if a.Address == address {
+			return a, nil
+		}
+	}
+
+	return tzkt.AccountInfo{Type: tzkt.AccountTypeEmpty, Address: address}, nil
+}
+
+func (c *Client) GetAccounts(filter tzkt.AccountFilter) ([]tzkt.AccountInfo, error) {
+	return c.GetAccountsWithContext(context.Background(), filter)
+}
+
+func (c *Client) GetAccountsWithContext(ctx context.Context, filter tzkt.AccountFilter) ([]tzkt.AccountInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	return page(c.accounts(filter), filter.Offset, limit), nil
+}
+
+func (c *Client) IterateAccounts(filter tzkt.AccountFilter, pageSize int) *tzkt.Iterator[tzkt.AccountInfo] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return sliceIterator(c.accounts(filter), pageSize, func(a tzkt.AccountInfo) uint64 { return a.ID }, c.Err)
+}
+
+// accounts returns the accounts matching filter sorted by id
+func (c *Client) accounts(filter tzkt.AccountFilter) []tzkt.AccountInfo {
+	in := func(v string, values []string) bool {
+		if len(values) == 0 {
+			return true
+		}
+		for _, value := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+
+	var result []tzkt.AccountInfo
+	for _, a := range c.Accounts {
+		switch {
+		case filter.Type != "" && a.Type != filter.Type,
+			filter.Kind != "" && a.Kind != filter.Kind,
+			filter.Delegate != "" && (a.Delegate == nil || a.Delegate.Address != filter.Delegate),
+			!in(a.Address, filter.Addresses),
+			a.Balance < filter.MinBalance,
+			a.LastActivity < filter.ActiveSince:
+			continue
+		}
+		result = append(result, a)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return result
+}
+
+func (c *Client) GetAccountOperations(address string, filter tzkt.OperationFilter) (tzkt.Operations, error) {
+	return c.GetAccountOperationsWithContext(context.Background(), address, filter)
+}
+
+func (c *Client) GetAccountOperationsWithContext(ctx context.Context, address string, filter tzkt.OperationFilter) (tzkt.Operations, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	return c.accountOperations(address, filter), nil
+}
+
+func (c *Client) IterateAccountOperations(address string, filter tzkt.OperationFilter, pageSize int) *tzkt.Iterator[tzkt.Operation] {
+	return tzkt.NewIterator(pageSize, func(o tzkt.Operation) uint64 { return o.Base().ID }, func(ctx context.Context, cursor uint64, limit int) ([]tzkt.Operation, error) {
+		f := filter
+		f.LastID = cursor
+		f.Limit = limit
+
+		return c.GetAccountOperationsWithContext(ctx, address, f)
+	})
+}
+
+// accountOperations returns a page of the operations of an address matching filter
+func (c *Client) accountOperations(address string, filter tzkt.OperationFilter) tzkt.Operations {
+	var result tzkt.Operations
+	for _, op := range c.AccountOperations[address] {
+		if matchOperation(op, filter) {
+			result = append(result, op)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if filter.Ascending {
+			return result[i].Base().ID < result[j].Base().ID
+		}
+		return result[i].Base().ID > result[j].Base().ID
+	})
+
+	if filter.LastID > 0 {
+		for i, op := range result {
+			if (filter.Ascending && op.Base().ID > filter.LastID) || (!filter.Ascending && op.Base().ID < filter.LastID) {
+				result = result[i:]
+				break
+			}
+			if i == len(result)-1 {
+				result = nil
+			}
+		}
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	return page(result, 0, limit)
+}
+
+func matchOperation(op tzkt.Operation, filter tzkt.OperationFilter) bool {
+	base := op.Base()
+
+	if len(filter.Types) > 0 {
+		found := false
+		for _, t := range filter.Types {
+			if base.Type == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filter.Entrypoint != "" {
+		tx, ok := op.(*tzkt.TransactionOperation)
+		if !ok || tx.Parameter == nil || tx.Parameter.EntryPoint != filter.Entrypoint {
+			return false
+		}
+	}
+
+	switch {
+	case filter.FromLevel > 0 && base.Level < filter.FromLevel,
+		filter.ToLevel > 0 && base.Level >= filter.ToLevel,
+		!filter.From.IsZero() && base.Timestamp.Before(filter.From),
+		!filter.To.IsZero() && !base.Timestamp.Before(filter.To),
+		filter.Status != "" && base.Status != filter.Status:
+		return false
+	}
+
+	return true
+}
+
+func (c *Client) GetAccountBalanceAtLevel(address string, level uint64) (int64, error) {
+	return c.GetAccountBalanceAtLevelWithContext(context.Background(), address, level)
+}
+
+// GetAccountBalanceAtLevelWithContext returns the last balance of the account
+// set at or before level, or zero
+func (c *Client) GetAccountBalanceAtLevelWithContext(ctx context.Context, address string, level uint64) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return 0, c.Err
+	}
+
+	var found *AccountBalance
+	for i, b := range c.AccountBalances {
+		if b.Address != address || b.Level > level {
+			continue
+		}
+		if found == nil || b.Level >= found.Level {
+			found = &c.AccountBalances[i]
+		}
+	}
+
+	if found == nil {
+		return 0, nil
+	}
+
+	return found.Balance, nil
+}
+
+func (c *Client) GetAccountBalanceAtTime(address string, at time.Time) (int64, error) {
+	return c.GetAccountBalanceAtTimeWithContext(context.Background(), address, at)
+}
+
+func (c *Client) GetAccountBalanceAtTimeWithContext(ctx context.Context, address string, at time.Time) (int64, error) {
+	level, err := c.GetLevelByTimeWithContext(ctx, at)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.GetAccountBalanceAtLevelWithContext(ctx, address, level)
+}
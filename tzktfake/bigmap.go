@@ -0,0 +1,174 @@
+package tzktfake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+)
+
+func (c *Client) GetBigMapValueByPointer(pointer int, key string) ([]byte, error) {
+	return c.GetBigMapValueByPointerWithContext(context.Background(), pointer, key)
+}
+
+func (c *Client) GetBigMapValueByPointerWithContext(ctx context.Context, pointer int, key string) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	for _, b := range c.Bigmaps {
+		if b.Pointer != pointer {
+			continue
+		}
+		if v, ok := b.Keys[key]; ok {
+			return v, nil
+		}
+	}
+
+	return nil, notFound("error key")
+}
+
+func (c *Client) GetBigMapPointersByContract(contract string, tags ...string) ([]int, error) {
+	return c.GetBigMapPointersByContractWithContext(context.Background(), contract, tags...)
+}
+
+func (c *Client) GetBigMapPointersByContractWithContext(ctx context.Context, contract string, tags ...string) ([]int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	hasAnyTag := func(b Bigmap) bool {
+		for _, t := range tags {
+			for _, bt := range b.Tags {
+				if t == bt {
+					return true
+				}
+			}
+		}
+		return len(tags) == 0
+	}
+
+	pointers := []int{}
+	for _, b := range c.Bigmaps {
+		if b.Contract == contract && hasAnyTag(b) {
+			pointers = append(pointers, b.Pointer)
+		}
+	}
+
+	return pointers, nil
+}
+
+func (c *Client) GetBigMapsByContractAndPath(contract string, path string) (int, error) {
+	return c.GetBigMapsByContractAndPathWithContext(context.Background(), contract, path)
+}
+
+func (c *Client) GetBigMapsByContractAndPathWithContext(ctx context.Context, contract string, path string) (int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return 0, c.Err
+	}
+
+	for _, b := range c.Bigmaps {
+		if b.Contract == contract && b.Path == path {
+			return b.Pointer, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no pointer: %w", tzkt.ErrNotFound)
+}
+
+func (c *Client) GetBigMapPointerForContractTokenMetadata(contract string) (int, error) {
+	return c.GetBigMapPointerForContractTokenMetadataWithContext(context.Background(), contract)
+}
+
+func (c *Client) GetBigMapPointerForContractTokenMetadataWithContext(ctx context.Context, contract string) (int, error) {
+	pointers, err := c.GetBigMapPointersByContractWithContext(ctx, contract, "token_metadata")
+	if err != nil {
+		return 0, err
+	}
+
+	if len(pointers) == 0 {
+		return 0, fmt.Errorf("no pointer: %w", tzkt.ErrNotFound)
+	}
+
+	return pointers[0], nil
+}
+
+// tokenMetadataUpdates returns the token_metadata bigmap updates of a level, sorted by id
+func (c *Client) tokenMetadataUpdates(level string) ([]tzkt.BigmapUpdate, error) {
+	l, err := strconv.ParseUint(level, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []tzkt.BigmapUpdate
+	for _, u := range c.BigmapUpdates {
+		if u.Level != l || u.Path != "token_metadata" || u.Action == "add_key" || u.Action == "allocate" {
+			continue
+		}
+		result = append(result, u)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return result, nil
+}
+
+func (c *Client) GetTokenMetadataBigmapUpdatesByLevel(level string, offset, limit int) ([]tzkt.BigmapUpdate, error) {
+	return c.GetTokenMetadataBigmapUpdatesByLevelWithContext(context.Background(), level, offset, limit)
+}
+
+func (c *Client) GetTokenMetadataBigmapUpdatesByLevelWithContext(ctx context.Context, level string, offset, limit int) ([]tzkt.BigmapUpdate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	if limit == 0 {
+		limit = 100
+	}
+
+	updates, err := c.tokenMetadataUpdates(level)
+	if err != nil {
+		return nil, err
+	}
+
+	return page(updates, offset, limit), nil
+}
+
+func (c *Client) StreamTokenMetadataBigmapUpdatesByLevel(level string, offset, limit int, fn func(tzkt.BigmapUpdate) error) error {
+	return c.StreamTokenMetadataBigmapUpdatesByLevelWithContext(context.Background(), level, offset, limit, fn)
+}
+
+func (c *Client) StreamTokenMetadataBigmapUpdatesByLevelWithContext(ctx context.Context, level string, offset, limit int, fn func(tzkt.BigmapUpdate) error) error {
+	updates, err := c.GetTokenMetadataBigmapUpdatesByLevelWithContext(ctx, level, offset, limit)
+	if err != nil {
+		return err
+	}
+
+	return stream(updates, fn)
+}
+
+func (c *Client) IterateTokenMetadataBigmapUpdatesByLevel(level string, pageSize int) *tzkt.Iterator[tzkt.BigmapUpdate] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	updates, err := c.tokenMetadataUpdates(level)
+	if c.Err != nil {
+		err = c.Err
+	}
+
+	return sliceIterator(updates, pageSize, func(u tzkt.BigmapUpdate) uint64 { return u.ID }, err)
+}
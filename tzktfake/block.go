@@ -0,0 +1,253 @@
+package tzktfake
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+)
+
+func (c *Client) GetHead() (tzkt.Head, error) {
+	return c.GetHeadWithContext(context.Background())
+}
+
+// GetHeadWithContext returns a synced head at the block of the highest level
+func (c *Client) GetHeadWithContext(ctx context.Context) (tzkt.Head, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return tzkt.Head{}, c.Err
+	}
+
+	head := tzkt.Head{Synced: true}
+	for _, b := range c.Blocks {
+		if b.Level >= head.Level {
+			head.Cycle = b.Cycle
+			head.Level = b.Level
+			head.Hash = b.Hash
+			head.Timestamp = b.Timestamp
+			head.KnownLevel = b.Level
+		}
+	}
+
+	return head, nil
+}
+
+func (c *Client) GetProtocol(code int) (tzkt.Protocol, error) {
+	return c.GetProtocolWithContext(context.Background(), code)
+}
+
+func (c *Client) GetProtocolWithContext(ctx context.Context, code int) (tzkt.Protocol, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return tzkt.Protocol{}, c.Err
+	}
+
+	for _, p := range c.Protocols {
+		if p.Code == code {
+			return p, nil
+		}
+	}
+
+	return tzkt.Protocol{}, notFound("protocol")
+}
+
+func (c *Client) GetBlock(levelOrHash string, operations bool) (tzkt.Block, error) {
+	return c.GetBlockWithContext(context.Background(), levelOrHash, operations)
+}
+
+func (c *Client) GetBlockWithContext(ctx context.Context, levelOrHash string, operations bool) (tzkt.Block, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return tzkt.Block{}, c.Err
+	}
+
+	level, err := strconv.ParseUint(levelOrHash, 10, 64)
+	isLevel := err == nil
+
+	for _, b := range c.Blocks {
+		if (isLevel && b.Level == level) || (!isLevel && b.Hash == levelOrHash) {
+			if !operations {
+				b = withoutOperations(b)
+			}
+			return b, nil
+		}
+	}
+
+	return tzkt.Block{}, notFound("block")
+}
+
+func (c *Client) GetBlocks(filter tzkt.BlockFilter) ([]tzkt.Block, error) {
+	return c.GetBlocksWithContext(context.Background(), filter)
+}
+
+func (c *Client) GetBlocksWithContext(ctx context.Context, filter tzkt.BlockFilter) ([]tzkt.Block, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	return page(c.blocks(filter), filter.Offset, limit), nil
+}
+
+func (c *Client) IterateBlocks(filter tzkt.BlockFilter, pageSize int) *tzkt.Iterator[tzkt.Block] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	blocks, err := c.blocks(filter), c.Err
+
+	// the level is the cursor and the genesis block is at level zero
+	started := false
+
+	return tzkt.NewIterator(pageSize, func(b tzkt.Block) uint64 { return b.Level }, func(ctx context.Context, cursor uint64, limit int) ([]tzkt.Block, error) {
+		if err != nil {
+			return nil, err
+		}
+
+		var result []tzkt.Block
+		for _, b := range blocks {
+			if (!started || b.Level > cursor) && len(result) < limit {
+				result = append(result, b)
+			}
+		}
+		started = true
+
+		return result, nil
+	})
+}
+
+// blocks returns the blocks matching filter sorted by level, without operations
+func (c *Client) blocks(filter tzkt.BlockFilter) []tzkt.Block {
+	var result []tzkt.Block
+	for _, b := range c.Blocks {
+		switch {
+		case b.Level < filter.FromLevel,
+			filter.ToLevel > 0 && b.Level >= filter.ToLevel,
+			b.Timestamp.Before(filter.From),
+			!filter.To.IsZero() && !b.Timestamp.Before(filter.To),
+			filter.Proposer != "" && (b.Proposer == nil || b.Proposer.Address != filter.Proposer):
+			continue
+		}
+		result = append(result, withoutOperations(b))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Level < result[j].Level })
+
+	return result
+}
+
+func withoutOperations(b tzkt.Block) tzkt.Block {
+	b.Transactions = nil
+	b.Originations = nil
+	b.Delegations = nil
+	b.Reveals = nil
+	b.OtherOperations = nil
+
+	return b
+}
+
+func (c *Client) GetLevelByTime(at time.Time) (uint64, error) {
+	return c.GetLevelByTimeWithContext(context.Background(), at)
+}
+
+// GetLevelByTimeWithContext returns the level of the last block produced at or before the given time
+func (c *Client) GetLevelByTimeWithContext(ctx context.Context, at time.Time) (uint64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return 0, c.Err
+	}
+
+	var found *Block
+	for i, b := range c.Blocks {
+		if b.Timestamp.After(at) {
+			continue
+		}
+		if found == nil || b.Timestamp.After(found.Timestamp) {
+			found = &c.Blocks[i]
+		}
+	}
+
+	if found == nil {
+		return 0, notFound("block")
+	}
+
+	return found.Level, nil
+}
+
+func (c *Client) GetTimeByLevel(level uint64) (time.Time, error) {
+	return c.GetTimeByLevelWithContext(context.Background(), level)
+}
+
+func (c *Client) GetTimeByLevelWithContext(ctx context.Context, level uint64) (time.Time, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return time.Time{}, c.Err
+	}
+
+	for _, b := range c.Blocks {
+		if b.Level == level {
+			return b.Timestamp, nil
+		}
+	}
+
+	return time.Time{}, notFound("block")
+}
+
+func (c *Client) GetLevelRange(from, to time.Time) (tzkt.LevelRange, error) {
+	return c.GetLevelRangeWithContext(context.Background(), from, to)
+}
+
+// GetLevelRangeWithContext returns the range following the levels of the last
+// blocks produced before from and to
+func (c *Client) GetLevelRangeWithContext(ctx context.Context, from, to time.Time) (tzkt.LevelRange, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return tzkt.LevelRange{}, c.Err
+	}
+
+	start, ok := c.levelBefore(from)
+	if !ok {
+		return tzkt.LevelRange{}, notFound("block")
+	}
+
+	end, ok := c.levelBefore(to)
+	if !ok {
+		return tzkt.LevelRange{}, notFound("block")
+	}
+
+	return tzkt.LevelRange{From: start + 1, To: end + 1}, nil
+}
+
+// levelBefore returns the level of the last block produced before at
+func (c *Client) levelBefore(at time.Time) (uint64, bool) {
+	var level uint64
+	found := false
+	for _, b := range c.Blocks {
+		if b.Timestamp.Before(at) && (!found || b.Level > level) {
+			level = b.Level
+			found = true
+		}
+	}
+
+	return level, found
+}
@@ -0,0 +1,226 @@
+// Package tzktfake provides an in-memory implementation of the tzkt.Client
+// interfaces for unit tests which should not do any http request.
+//
+//	fake := &tzktfake.Client{
+//		Tokens: []tzkt.Token{token},
+//	}
+//	svc := NewService(fake) // NewService accepts a tzkt.TokenReader
+package tzktfake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+)
+
+// Balance is the balance of a token held by an owner
+type Balance struct {
+	Owner string
+	tzkt.OwnedToken
+}
+
+// AccountBalance is the balance of an account from a block level onwards
+type AccountBalance struct {
+	Address string
+	Level   uint64
+	Balance int64
+}
+
+// TokenBalanceChange is the balance of a token held by an account from a block
+// level onwards
+type TokenBalanceChange struct {
+	Level uint64
+	tzkt.HistoricalTokenBalance
+}
+
+// Bigmap is a bigmap of a contract with its keys
+type Bigmap struct {
+	Pointer  int
+	Contract string
+	Path     string
+	Tags     []string
+	Keys     map[string]json.RawMessage
+}
+
+// Block is a block of the chain, with the operations served when they are
+// requested
+type Block = tzkt.Block
+
+// Client is an in-memory tzkt.Client. Its fields are the data served by the
+// methods and can be set directly; use the Add methods when the client is
+// shared by goroutines.
+type Client struct {
+	mu sync.RWMutex
+
+	Tokens        []tzkt.Token
+	Balances      []Balance
+	Transfers     []tzkt.TokenTransfer
+	Transactions  []tzkt.DetailedTransaction
+	Bigmaps       []Bigmap
+	BigmapUpdates []tzkt.BigmapUpdate
+	Blocks        []Block
+	Protocols     []tzkt.Protocol
+	Accounts      []tzkt.AccountInfo
+	// AccountBalances and TokenBalanceChanges are the history of the balances
+	AccountBalances     []AccountBalance
+	TokenBalanceChanges []TokenBalanceChange
+	// AccountOperations are the operations related to each address
+	AccountOperations map[string][]tzkt.Operation
+
+	// Err, when set, is returned by every method
+	Err error
+}
+
+var _ tzkt.Client = (*Client)(nil)
+
+// AddTokens adds tokens to the fake
+func (c *Client) AddTokens(tokens ...tzkt.Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Tokens = append(c.Tokens, tokens...)
+}
+
+// AddBalances adds balances to the fake
+func (c *Client) AddBalances(balances ...Balance) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Balances = append(c.Balances, balances...)
+}
+
+// AddTransfers adds token transfers to the fake
+func (c *Client) AddTransfers(transfers ...tzkt.TokenTransfer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Transfers = append(c.Transfers, transfers...)
+}
+
+// AddTransactions adds transactions to the fake
+func (c *Client) AddTransactions(transactions ...tzkt.DetailedTransaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Transactions = append(c.Transactions, transactions...)
+}
+
+// AddBigmaps adds bigmaps to the fake
+func (c *Client) AddBigmaps(bigmaps ...Bigmap) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Bigmaps = append(c.Bigmaps, bigmaps...)
+}
+
+// AddBigmapUpdates adds bigmap updates to the fake
+func (c *Client) AddBigmapUpdates(updates ...tzkt.BigmapUpdate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.BigmapUpdates = append(c.BigmapUpdates, updates...)
+}
+
+// AddBlocks adds blocks to the fake
+func (c *Client) AddBlocks(blocks ...Block) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Blocks = append(c.Blocks, blocks...)
+}
+
+// AddProtocols adds protocols to the fake
+func (c *Client) AddProtocols(protocols ...tzkt.Protocol) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Protocols = append(c.Protocols, protocols...)
+}
+
+// AddAccounts adds accounts to the fake
+func (c *Client) AddAccounts(accounts ...tzkt.AccountInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Accounts = append(c.Accounts, accounts...)
+}
+
+// AddAccountBalances adds changes of account balances to the fake
+func (c *Client) AddAccountBalances(balances ...AccountBalance) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.AccountBalances = append(c.AccountBalances, balances...)
+}
+
+// AddTokenBalanceChanges adds changes of token balances to the fake
+func (c *Client) AddTokenBalanceChanges(changes ...TokenBalanceChange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.TokenBalanceChanges = append(c.TokenBalanceChanges, changes...)
+}
+
+// AddAccountOperations adds operations related to an address to the fake
+func (c *Client) AddAccountOperations(address string, operations ...tzkt.Operation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.AccountOperations == nil {
+		c.AccountOperations = map[string][]tzkt.Operation{}
+	}
+	c.AccountOperations[address] = append(c.AccountOperations[address], operations...)
+}
+
+func notFound(what string) error {
+	return fmt.Errorf("%s %w", what, tzkt.ErrNotFound)
+}
+
+// page returns the items between offset and offset+limit
+func page[T any](items []T, offset, limit int) []T {
+	if offset > len(items) {
+		offset = len(items)
+	}
+	items = items[offset:]
+
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// stream passes the items to fn until it fails
+func stream[T any](items []T, fn func(T) error) error {
+	for _, item := range items {
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sliceIterator returns an Iterator over items sorted by id, failing with err if not nil
+func sliceIterator[T any](items []T, pageSize int, id func(T) uint64, err error) *tzkt.Iterator[T] {
+	sort.SliceStable(items, func(i, j int) bool { return id(items[i]) < id(items[j]) })
+
+	return tzkt.NewIterator(pageSize, id, func(ctx context.Context, cursor uint64, limit int) ([]T, error) {
+		if err != nil {
+			return nil, err
+		}
+
+		var result []T
+		for _, item := range items {
+			if id(item) > cursor && len(result) < limit {
+				result = append(result, item)
+			}
+		}
+
+		return result, nil
+	})
+}
@@ -0,0 +1,291 @@
+package tzktfake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+)
+
+func tokenID(id int64) tzkt.TokenID {
+	return tzkt.TokenID{Int: *big.NewInt(id)}
+}
+
+func TestTokens(t *testing.T) {
+	token := tzkt.Token{Contract: tzkt.Account{Address: "KT1a"}, ID: tokenID(1), Standard: "fa2", TotalSupply: 3}
+	at := time.Date(2023, 4, 20, 7, 9, 1, 0, time.UTC)
+
+	fake := &Client{}
+	fake.AddTokens(token)
+	fake.AddBalances(
+		Balance{Owner: "tz1a", OwnedToken: tzkt.OwnedToken{ID: 1, Token: token, Balance: 2, LastTime: at}},
+		Balance{Owner: "tz1b", OwnedToken: tzkt.OwnedToken{ID: 2, Token: token, Balance: 1, LastTime: at.Add(-time.Hour)}},
+	)
+
+	var reader tzkt.TokenReader = fake
+
+	got, err := reader.GetContractToken("KT1a", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, token.Contract, got.Contract)
+
+	_, err = reader.GetContractToken("KT1a", "2")
+	assert.ErrorIs(t, err, tzkt.ErrNotFound)
+
+	owners, err := reader.GetTokenOwners("KT1a", "1", 10, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, owners, 2)
+	assert.Equal(t, "tz1b", owners[0].Address)
+	assert.Equal(t, int64(3), owners[0].TotalSupply)
+
+	balance, lastTime, err := reader.GetTokenBalanceAndLastTimeForOwner("KT1a", "1", "tz1a")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), balance)
+	assert.Equal(t, at, lastTime)
+
+	it := reader.IterateOwnedTokens("tz1a", time.Time{}, 1)
+	count := 0
+	for it.Next(context.Background()) {
+		count++
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 1, count)
+}
+
+func TestOperationsAndBigmaps(t *testing.T) {
+	applied := tzkt.DetailedTransaction{ID: 1, Hash: "oo1", Status: "applied", Target: tzkt.Account{Address: "KT1a"}, Parameter: &tzkt.TransactionParameter{EntryPoint: "mint"}}
+	failed := tzkt.DetailedTransaction{ID: 2, Hash: "oo2", Status: "failed", Target: tzkt.Account{Address: "KT1a"}, Parameter: &tzkt.TransactionParameter{EntryPoint: "transfer"}}
+
+	fake := &Client{
+		Transactions: []tzkt.DetailedTransaction{applied, failed},
+		Bigmaps: []Bigmap{
+			{Pointer: 514, Contract: "KT1a", Path: "token_metadata", Tags: []string{"token_metadata"}, Keys: map[string]json.RawMessage{"1": json.RawMessage(`{"token_id":"1"}`)}},
+		},
+		Blocks: []Block{{Level: 10, Timestamp: time.Unix(100, 0)}, {Level: 11, Timestamp: time.Unix(130, 0)}},
+	}
+
+	status, err := fake.GetTransactionStatusByTx("oo2")
+	assert.NoError(t, err)
+	assert.False(t, *status)
+
+	status, err = fake.GetTransactionStatusByTx("oo3")
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	txs, err := fake.GetTransactions([]string{"KT1a"}, []string{"mint"}, nil, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, "oo1", txs[0].Hash)
+
+	p, err := fake.GetBigMapPointerForContractTokenMetadata("KT1a")
+	assert.NoError(t, err)
+	assert.Equal(t, 514, p)
+
+	v, err := fake.GetBigMapValueByPointer(p, "1")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"token_id":"1"}`, string(v))
+
+	level, err := fake.GetLevelByTime(time.Unix(120, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), level)
+}
+
+func TestErr(t *testing.T) {
+	boom := errors.New("boom")
+	fake := &Client{Err: boom}
+
+	_, err := fake.GetTokenTransfersCount("KT1a", "1")
+	assert.ErrorIs(t, err, boom)
+
+	it := fake.IterateTokenTransfers("KT1a", "1", 10)
+	assert.False(t, it.Next(context.Background()))
+	assert.ErrorIs(t, it.Err(), boom)
+}
+
+func TestStream(t *testing.T) {
+	fake := &Client{}
+	fake.AddTransfers(
+		tzkt.TokenTransfer{ID: 2, Level: 10},
+		tzkt.TokenTransfer{ID: 1, Level: 10},
+		tzkt.TokenTransfer{ID: 3, Level: 11},
+	)
+
+	var ids []uint64
+	err := fake.StreamTokenTransfersByLevel("10", 0, 0, func(t tzkt.TokenTransfer) error {
+		ids = append(ids, t.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2}, ids)
+
+	stop := errors.New("stop")
+	err = fake.StreamTokenTransfersByLevel("10", 0, 0, func(t tzkt.TokenTransfer) error { return stop })
+	assert.ErrorIs(t, err, stop)
+	it := fake.IterateTokenTransfersByLevel("head", 10)
+	assert.False(t, it.Next(context.Background()))
+	assert.Error(t, it.Err())
+
+	bigmapIt := fake.IterateTokenMetadataBigmapUpdatesByLevel("head", 10)
+	assert.False(t, bigmapIt.Next(context.Background()))
+	assert.Error(t, bigmapIt.Err())
+}
+
+func TestAccounts(t *testing.T) {
+	fake := &Client{}
+	fake.AddAccounts(
+		tzkt.AccountInfo{ID: 2, Type: tzkt.AccountTypeContract, Address: "KT1a", Kind: "asset"},
+		tzkt.AccountInfo{ID: 1, Type: tzkt.AccountTypeUser, Address: "tz1a", Balance: 5},
+	)
+
+	var reader tzkt.AccountReader = fake
+
+	account, err := reader.GetAccount("tz1a")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), account.Balance)
+
+	account, err = reader.GetAccount("tz1b")
+	assert.NoError(t, err)
+	assert.Equal(t, tzkt.AccountTypeEmpty, account.Type)
+
+	accounts, err := reader.GetAccounts(tzkt.AccountFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2)
+	assert.Equal(t, "tz1a", accounts[0].Address)
+
+	accounts, err = reader.GetAccounts(tzkt.AccountFilter{Type: tzkt.AccountTypeContract})
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, "KT1a", accounts[0].Address)
+}
+
+func TestAccountOperations(t *testing.T) {
+	fake := &Client{}
+	fake.AddAccountOperations("tz1a",
+		&tzkt.RevealOperation{OperationBase: tzkt.OperationBase{Type: tzkt.OperationTypeReveal, ID: 1, Level: 10}},
+		&tzkt.TransactionOperation{
+			OperationBase: tzkt.OperationBase{Type: tzkt.OperationTypeTransaction, ID: 2, Level: 11},
+			Parameter:     &tzkt.TransactionParameter{EntryPoint: "mint"},
+		},
+		&tzkt.TransactionOperation{OperationBase: tzkt.OperationBase{Type: tzkt.OperationTypeTransaction, ID: 3, Level: 12}},
+	)
+
+	ops, err := fake.GetAccountOperations("tz1a", tzkt.OperationFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, ops, 3)
+	assert.Equal(t, uint64(3), ops[0].Base().ID)
+
+	ops, err = fake.GetAccountOperations("tz1a", tzkt.OperationFilter{Types: []tzkt.OperationType{tzkt.OperationTypeTransaction}, Entrypoint: "mint"})
+	assert.NoError(t, err)
+	assert.Len(t, ops, 1)
+	assert.Equal(t, uint64(2), ops[0].Base().ID)
+
+	ops, err = fake.GetAccountOperations("tz1a", tzkt.OperationFilter{LastID: 3, Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, ops, 1)
+	assert.Equal(t, uint64(2), ops[0].Base().ID)
+
+	it := fake.IterateAccountOperations("tz1a", tzkt.OperationFilter{Ascending: true}, 2)
+	var ids []uint64
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().Base().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []uint64{1, 2, 3}, ids)
+}
+
+func TestBalanceHistory(t *testing.T) {
+	at := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := tzkt.Token{Contract: tzkt.Account{Address: "KT1a"}, ID: tokenID(1)}
+
+	fake := &Client{}
+	fake.AddBlocks(Block{Level: 100, Timestamp: at})
+	fake.AddAccountBalances(
+		AccountBalance{Address: "tz1a", Level: 50, Balance: 10},
+		AccountBalance{Address: "tz1a", Level: 150, Balance: 20},
+	)
+	fake.AddTokenBalanceChanges(
+		TokenBalanceChange{Level: 50, HistoricalTokenBalance: tzkt.HistoricalTokenBalance{ID: 1, Account: tzkt.Account{Address: "tz1a"}, Token: token, Balance: 1}},
+		TokenBalanceChange{Level: 90, HistoricalTokenBalance: tzkt.HistoricalTokenBalance{ID: 1, Account: tzkt.Account{Address: "tz1a"}, Token: token, Balance: 0}},
+		TokenBalanceChange{Level: 90, HistoricalTokenBalance: tzkt.HistoricalTokenBalance{ID: 2, Account: tzkt.Account{Address: "tz1b"}, Token: token, Balance: 1}},
+	)
+
+	balance, err := fake.GetAccountBalanceAtTime("tz1a", at.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), balance)
+
+	balances, err := fake.GetTokenBalancesAtLevel("KT1a", "1", "", 60)
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+	assert.Equal(t, "tz1a", balances[0].Account.Address)
+
+	balances, err = fake.GetTokenBalancesAtTime("KT1a", "1", "", at)
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+	assert.Equal(t, "tz1b", balances[0].Account.Address)
+
+	_, err = fake.GetTokenBalancesAtLevel("", "1", "", 60)
+	assert.ErrorIs(t, err, tzkt.ErrBadRequest)
+}
+
+func TestBlocks(t *testing.T) {
+	at := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	fake := &Client{}
+	fake.AddBlocks(
+		Block{Level: 101, Hash: "BLb", Timestamp: at.Add(8 * time.Second), Proto: 18,
+			Transactions:    []tzkt.TransactionOperation{{OperationBase: tzkt.OperationBase{ID: 1}}},
+			OtherOperations: tzkt.Operations{&tzkt.UnknownOperation{OperationBase: tzkt.OperationBase{Type: "endorsement", ID: 2}}}},
+		Block{Level: 100, Hash: "BLa", Timestamp: at},
+	)
+	fake.AddProtocols(tzkt.Protocol{Code: 18, Hash: "Proxford"})
+
+	head, err := fake.GetHead()
+	assert.NoError(t, err)
+	assert.Equal(t, "BLb", head.Hash)
+
+	block, err := fake.GetBlock("BLb", true)
+	assert.NoError(t, err)
+	assert.Len(t, block.Operations(), 2)
+
+	protocol, err := fake.GetProtocol(block.Proto)
+	assert.NoError(t, err)
+	assert.Equal(t, "Proxford", protocol.Hash)
+
+	block, err = fake.GetBlock("101", false)
+	assert.NoError(t, err)
+	assert.Empty(t, block.Operations())
+
+	_, err = fake.GetBlock("102", false)
+	assert.ErrorIs(t, err, tzkt.ErrNotFound)
+
+	blocks, err := fake.GetBlocks(tzkt.BlockFilter{To: at.Add(time.Second)})
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 1)
+	assert.Equal(t, uint64(100), blocks[0].Level)
+
+	timestamp, err := fake.GetTimeByLevel(101)
+	assert.NoError(t, err)
+	assert.Equal(t, at.Add(8*time.Second), timestamp)
+
+	r, err := fake.GetLevelRange(at.Add(time.Second), at.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, tzkt.LevelRange{From: 101, To: 102}, r)
+}
+
+func TestIterateBlocksFromGenesis(t *testing.T) {
+	fake := &Client{}
+	fake.AddBlocks(Block{Level: 1, Hash: "BLfirst"}, Block{Level: 0, Hash: "BLgenesis"})
+
+	var levels []uint64
+	it := fake.IterateBlocks(tzkt.BlockFilter{}, 1)
+	for it.Next(context.Background()) && len(levels) < 5 {
+		levels = append(levels, it.Item().Level)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []uint64{0, 1}, levels)
+}
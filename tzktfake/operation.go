@@ -0,0 +1,140 @@
+package tzktfake
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+)
+
+func (c *Client) GetTransactionStatusByTx(hash string) (*bool, error) {
+	return c.GetTransactionStatusByTxWithContext(context.Background(), hash)
+}
+
+// GetTransactionStatusByTxWithContext returns true for an applied transaction,
+// false for a failed one and nil for an unknown or pending one
+func (c *Client) GetTransactionStatusByTxWithContext(ctx context.Context, hash string) (*bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	for _, tx := range c.Transactions {
+		if tx.Hash != hash || tx.Status == "" {
+			continue
+		}
+
+		status := tx.Status == "applied"
+		return &status, nil
+	}
+
+	return nil, nil
+}
+
+func (c *Client) GetTransactionByTx(hash string) ([]tzkt.DetailedTransaction, error) {
+	return c.GetTransactionByTxWithContext(context.Background(), hash)
+}
+
+func (c *Client) GetTransactionByTxWithContext(ctx context.Context, hash string) ([]tzkt.DetailedTransaction, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	txs := []tzkt.DetailedTransaction{}
+	for _, tx := range c.Transactions {
+		if tx.Hash == hash {
+			txs = append(txs, tx)
+		}
+	}
+
+	return txs, nil
+}
+
+func toTransaction(tx tzkt.DetailedTransaction) tzkt.Transaction {
+	return tzkt.Transaction{
+		Block:     tx.Block,
+		Target:    tx.Target,
+		Timestamp: tx.Timestamp,
+		ID:        tx.ID,
+		Hash:      tx.Hash,
+	}
+}
+
+func (c *Client) GetTransaction(id uint64) (tzkt.Transaction, error) {
+	return c.GetTransactionWithContext(context.Background(), id)
+}
+
+func (c *Client) GetTransactionWithContext(ctx context.Context, id uint64) (tzkt.Transaction, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return tzkt.Transaction{}, c.Err
+	}
+
+	for _, tx := range c.Transactions {
+		if tx.ID == id {
+			return toTransaction(tx), nil
+		}
+	}
+
+	return tzkt.Transaction{}, notFound("transaction")
+}
+
+// transactions returns the transactions calling the entrypoints of the contracts after lastTime, sorted by id
+func (c *Client) transactions(contracts []string, entrypoints []string, lastTime *time.Time) []tzkt.Transaction {
+	in := func(v string, values []string) bool {
+		for _, value := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+
+	var result []tzkt.Transaction
+	for _, tx := range c.Transactions {
+		if !in(tx.Target.Address, contracts) {
+			continue
+		}
+		if tx.Parameter == nil || !in(tx.Parameter.EntryPoint, entrypoints) {
+			continue
+		}
+		if lastTime != nil && !tx.Timestamp.After(*lastTime) {
+			continue
+		}
+		result = append(result, toTransaction(tx))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return result
+}
+
+func (c *Client) GetTransactions(contracts []string, entrypoints []string, lastTime *time.Time, offset, limit int) ([]tzkt.Transaction, error) {
+	return c.GetTransactionsWithContext(context.Background(), contracts, entrypoints, lastTime, offset, limit)
+}
+
+func (c *Client) GetTransactionsWithContext(ctx context.Context, contracts []string, entrypoints []string, lastTime *time.Time, offset, limit int) ([]tzkt.Transaction, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	return page(c.transactions(contracts, entrypoints, lastTime), offset, limit), nil
+}
+
+func (c *Client) IterateTransactions(contracts []string, entrypoints []string, lastTime *time.Time, pageSize int) *tzkt.Iterator[tzkt.Transaction] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return sliceIterator(c.transactions(contracts, entrypoints, lastTime), pageSize, func(t tzkt.Transaction) uint64 { return t.ID }, c.Err)
+}
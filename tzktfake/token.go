@@ -0,0 +1,395 @@
+package tzktfake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+)
+
+func (c *Client) GetContractToken(contract, tokenID string) (tzkt.Token, error) {
+	return c.GetContractTokenWithContext(context.Background(), contract, tokenID)
+}
+
+func (c *Client) GetContractTokenWithContext(ctx context.Context, contract, tokenID string) (tzkt.Token, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return tzkt.Token{}, c.Err
+	}
+
+	for _, t := range c.Tokens {
+		if t.Contract.Address == contract && t.ID.String() == tokenID {
+			return t, nil
+		}
+	}
+
+	return tzkt.Token{}, notFound("token")
+}
+
+// balances returns the fa2 balances of a token, of every token when contract is empty,
+// for an owner or every owner when owner is empty
+func (c *Client) balances(contract, tokenID, owner string) []Balance {
+	var result []Balance
+	for _, b := range c.Balances {
+		if contract != "" && (b.Token.Contract.Address != contract || b.Token.ID.String() != tokenID) {
+			continue
+		}
+		if owner != "" && b.Owner != owner {
+			continue
+		}
+		if b.Token.Standard != "" && b.Token.Standard != "fa2" {
+			continue
+		}
+		result = append(result, b)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].LastTime.Before(result[j].LastTime) })
+
+	return result
+}
+
+func (c *Client) GetTokenBalanceOfOwner(contract, tokenID, owner string) (int64, error) {
+	return c.GetTokenBalanceOfOwnerWithContext(context.Background(), contract, tokenID, owner)
+}
+
+// GetTokenBalanceOfOwnerWithContext counts the balance records of the owner like the api does
+func (c *Client) GetTokenBalanceOfOwnerWithContext(ctx context.Context, contract, tokenID, owner string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return 0, c.Err
+	}
+
+	return int64(len(c.balances(contract, tokenID, owner))), nil
+}
+
+func (c *Client) GetTokenOwners(contract, tokenID string, limit int, lastTime time.Time) ([]tzkt.TokenOwner, error) {
+	return c.GetTokenOwnersWithContext(context.Background(), contract, tokenID, limit, lastTime)
+}
+
+func (c *Client) GetTokenOwnersWithContext(ctx context.Context, contract, tokenID string, limit int, lastTime time.Time) ([]tzkt.TokenOwner, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	owners := []tzkt.TokenOwner{}
+	for _, b := range c.balances(contract, tokenID, "") {
+		if b.Balance <= 0 || b.LastTime.Before(lastTime) {
+			continue
+		}
+		owners = append(owners, tzkt.TokenOwner{
+			Address:     b.Owner,
+			Balance:     int64(b.Balance),
+			LastTime:    b.LastTime,
+			TotalSupply: int64(b.Token.TotalSupply),
+		})
+	}
+
+	return page(owners, 0, limit), nil
+}
+
+func (c *Client) GetTokenBalanceAndLastTimeForOwner(contract, tokenID, owner string) (int64, time.Time, error) {
+	return c.GetTokenBalanceAndLastTimeForOwnerWithContext(context.Background(), contract, tokenID, owner)
+}
+
+func (c *Client) GetTokenBalanceAndLastTimeForOwnerWithContext(ctx context.Context, contract, tokenID, owner string) (int64, time.Time, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return 0, time.Time{}, c.Err
+	}
+
+	var owned []Balance
+	for _, b := range c.balances(contract, tokenID, owner) {
+		if b.Balance > 0 {
+			owned = append(owned, b)
+		}
+	}
+
+	if len(owned) == 0 {
+		return 0, time.Time{}, notFound("token")
+	}
+
+	if len(owned) > 1 {
+		return 0, time.Time{}, fmt.Errorf("multiple token owners returned")
+	}
+
+	return int64(owned[0].Balance), owned[0].LastTime, nil
+}
+
+// transfers returns the transfers of a token sorted by id
+func (c *Client) transfers(contract, tokenID string) []tzkt.TokenTransfer {
+	var result []tzkt.TokenTransfer
+	for _, t := range c.Transfers {
+		if t.Token != nil && t.Token.Contract.Address == contract && t.Token.ID.String() == tokenID {
+			result = append(result, t)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return result
+}
+
+func (c *Client) GetTokenLastActivityTime(contract, tokenID string) (time.Time, error) {
+	return c.GetTokenLastActivityTimeWithContext(context.Background(), contract, tokenID)
+}
+
+func (c *Client) GetTokenLastActivityTimeWithContext(ctx context.Context, contract, tokenID string) (time.Time, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return time.Time{}, c.Err
+	}
+
+	var last time.Time
+	for _, t := range c.transfers(contract, tokenID) {
+		if t.Timestamp.After(last) {
+			last = t.Timestamp
+		}
+	}
+
+	if last.IsZero() {
+		return time.Time{}, fmt.Errorf("no activities for this token: %w", tzkt.ErrNotFound)
+	}
+
+	return last, nil
+}
+
+func (c *Client) GetTokenTransfers(contract, tokenID string, limit int) ([]tzkt.TokenTransfer, error) {
+	return c.GetTokenTransfersWithContext(context.Background(), contract, tokenID, limit)
+}
+
+func (c *Client) GetTokenTransfersWithContext(ctx context.Context, contract, tokenID string, limit int) ([]tzkt.TokenTransfer, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	if limit == 0 {
+		limit = 100
+	}
+
+	return page(c.transfers(contract, tokenID), 0, limit), nil
+}
+
+// transfersByLevel returns the transfers of a level sorted by id
+func (c *Client) transfersByLevel(level string) ([]tzkt.TokenTransfer, error) {
+	l, err := strconv.ParseUint(level, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []tzkt.TokenTransfer
+	for _, t := range c.Transfers {
+		if t.Level == l {
+			result = append(result, t)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return result, nil
+}
+
+func (c *Client) GetTokenTransfersByLevel(level string, offset, limit int) ([]tzkt.TokenTransfer, error) {
+	return c.GetTokenTransfersByLevelWithContext(context.Background(), level, offset, limit)
+}
+
+func (c *Client) GetTokenTransfersByLevelWithContext(ctx context.Context, level string, offset, limit int) ([]tzkt.TokenTransfer, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	if limit == 0 {
+		limit = 100
+	}
+
+	transfers, err := c.transfersByLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	return page(transfers, offset, limit), nil
+}
+
+func (c *Client) StreamTokenTransfersByLevel(level string, offset, limit int, fn func(tzkt.TokenTransfer) error) error {
+	return c.StreamTokenTransfersByLevelWithContext(context.Background(), level, offset, limit, fn)
+}
+
+func (c *Client) StreamTokenTransfersByLevelWithContext(ctx context.Context, level string, offset, limit int, fn func(tzkt.TokenTransfer) error) error {
+	transfers, err := c.GetTokenTransfersByLevelWithContext(ctx, level, offset, limit)
+	if err != nil {
+		return err
+	}
+
+	return stream(transfers, fn)
+}
+
+func (c *Client) GetTokenTransfersCount(contract, tokenID string) (int, error) {
+	return c.GetTokenTransfersCountWithContext(context.Background(), contract, tokenID)
+}
+
+func (c *Client) GetTokenTransfersCountWithContext(ctx context.Context, contract, tokenID string) (int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return 0, c.Err
+	}
+
+	return len(c.transfers(contract, tokenID)), nil
+}
+
+// ownedTokens returns the tokens of an owner updated after lastTime
+func (c *Client) ownedTokens(owner string, lastTime time.Time) []tzkt.OwnedToken {
+	var result []tzkt.OwnedToken
+	for _, b := range c.balances("", "", owner) {
+		if b.Balance >= 0 && b.LastTime.After(lastTime) {
+			result = append(result, b.OwnedToken)
+		}
+	}
+
+	return result
+}
+
+func (c *Client) RetrieveTokens(owner string, lastTime time.Time, offset int) ([]tzkt.OwnedToken, error) {
+	return c.RetrieveTokensWithContext(context.Background(), owner, lastTime, offset)
+}
+
+func (c *Client) RetrieveTokensWithContext(ctx context.Context, owner string, lastTime time.Time, offset int) ([]tzkt.OwnedToken, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	return page(c.ownedTokens(owner, lastTime), offset, 50), nil
+}
+
+func (c *Client) IterateTokenTransfers(contract, tokenID string, pageSize int) *tzkt.Iterator[tzkt.TokenTransfer] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return sliceIterator(c.transfers(contract, tokenID), pageSize, func(t tzkt.TokenTransfer) uint64 { return t.ID }, c.Err)
+}
+
+func (c *Client) IterateTokenTransfersByLevel(level string, pageSize int) *tzkt.Iterator[tzkt.TokenTransfer] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	transfers, err := c.transfersByLevel(level)
+	if c.Err != nil {
+		err = c.Err
+	}
+
+	return sliceIterator(transfers, pageSize, func(t tzkt.TokenTransfer) uint64 { return t.ID }, err)
+}
+
+func (c *Client) IterateOwnedTokens(owner string, lastTime time.Time, pageSize int) *tzkt.Iterator[tzkt.OwnedToken] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return sliceIterator(c.ownedTokens(owner, lastTime), pageSize, func(t tzkt.OwnedToken) uint64 { return t.ID }, c.Err)
+}
+
+// errNoBalancesFilter is returned, like by tzkt, for the historical balances
+// of every token and owner
+var errNoBalancesFilter = fmt.Errorf("%w: an owner or a token contract is required", tzkt.ErrBadRequest)
+
+func (c *Client) GetTokenBalancesAtLevel(contract, tokenID, owner string, level uint64) ([]tzkt.HistoricalTokenBalance, error) {
+	return c.GetTokenBalancesAtLevelWithContext(context.Background(), contract, tokenID, owner, level)
+}
+
+// GetTokenBalancesAtLevelWithContext returns the last non-zero balances of the
+// matching tokens and owners set at or before level
+func (c *Client) GetTokenBalancesAtLevelWithContext(ctx context.Context, contract, tokenID, owner string, level uint64) ([]tzkt.HistoricalTokenBalance, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	if contract == "" && owner == "" {
+		return nil, errNoBalancesFilter
+	}
+
+	return c.tokenBalancesAtLevel(contract, tokenID, owner, level), nil
+}
+
+func (c *Client) GetTokenBalancesAtTime(contract, tokenID, owner string, at time.Time) ([]tzkt.HistoricalTokenBalance, error) {
+	return c.GetTokenBalancesAtTimeWithContext(context.Background(), contract, tokenID, owner, at)
+}
+
+func (c *Client) GetTokenBalancesAtTimeWithContext(ctx context.Context, contract, tokenID, owner string, at time.Time) ([]tzkt.HistoricalTokenBalance, error) {
+	level, err := c.GetLevelByTimeWithContext(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetTokenBalancesAtLevelWithContext(ctx, contract, tokenID, owner, level)
+}
+
+func (c *Client) IterateTokenBalancesAtLevel(contract, tokenID, owner string, level uint64, pageSize int) *tzkt.Iterator[tzkt.HistoricalTokenBalance] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	err := c.Err
+	if err == nil && contract == "" && owner == "" {
+		err = errNoBalancesFilter
+	}
+
+	balances := c.tokenBalancesAtLevel(contract, tokenID, owner, level)
+
+	return sliceIterator(balances, pageSize, func(b tzkt.HistoricalTokenBalance) uint64 { return b.ID }, err)
+}
+
+// tokenBalancesAtLevel returns the balances at level sorted by id
+func (c *Client) tokenBalancesAtLevel(contract, tokenID, owner string, level uint64) []tzkt.HistoricalTokenBalance {
+	type key struct {
+		owner, contract, tokenID string
+	}
+
+	latest := map[key]TokenBalanceChange{}
+	for _, b := range c.TokenBalanceChanges {
+		if b.Level > level ||
+			(contract != "" && b.Token.Contract.Address != contract) ||
+			(tokenID != "" && b.Token.ID.String() != tokenID) ||
+			(owner != "" && b.Account.Address != owner) {
+			continue
+		}
+
+		k := key{b.Account.Address, b.Token.Contract.Address, b.Token.ID.String()}
+		if l, ok := latest[k]; !ok || b.Level >= l.Level {
+			latest[k] = b
+		}
+	}
+
+	var balances []tzkt.HistoricalTokenBalance
+	for _, b := range latest {
+		if b.Balance != 0 {
+			balances = append(balances, b.HistoricalTokenBalance)
+		}
+	}
+
+	sort.SliceStable(balances, func(i, j int) bool { return balances[i].ID < balances[j].ID })
+
+	return balances
+}
@@ -0,0 +1,157 @@
+// Package tzktotel traces the api requests of a tzkt client with OpenTelemetry.
+//
+//	client := tzkt.New("mainnet", tzkt.WithMiddleware(tzktotel.Middleware()))
+//
+// Every attempt of a request is a span, child of the span in the context given
+// to the client method, named after the method and carrying the path, the
+// query filters, the status code, the number of results and the attempt.
+package tzktotel
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+)
+
+// instrumentationName identifies the tracer of the package
+const instrumentationName = "github.com/bitmark-inc/tzkt-go/tzktotel"
+
+// attribute keys of the spans; each query parameter is an attribute named
+// QueryKeyPrefix followed by the parameter, e.g. tzkt.query.level.gt
+const (
+	MethodKey      = attribute.Key("tzkt.method")
+	PathKey        = attribute.Key("tzkt.path")
+	QueryKeyPrefix = "tzkt.query."
+	StatusCodeKey  = attribute.Key("http.status_code")
+	ResultCountKey = attribute.Key("tzkt.result_count")
+	AttemptKey     = attribute.Key("tzkt.attempt")
+)
+
+type config struct {
+	provider    trace.TracerProvider
+	propagators propagation.TextMapPropagator
+}
+
+// Option configures the Middleware
+type Option func(*config)
+
+// WithTracerProvider creates the spans with provider instead of the global one
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagators injects the trace context into the requests with propagators
+// instead of the global ones
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = propagators
+	}
+}
+
+// Middleware returns a tzkt.Middleware emitting a span for every api request
+func Middleware(options ...Option) tzkt.Middleware {
+	c := &config{
+		provider:    otel.GetTracerProvider(),
+		propagators: otel.GetTextMapPropagator(),
+	}
+	for _, o := range options {
+		o(c)
+	}
+
+	tracer := c.provider.Tracer(instrumentationName)
+
+	return func(next tzkt.Doer) tzkt.Doer {
+		return tzkt.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			method := tzkt.Method(req.Context())
+
+			ctx, span := tracer.Start(req.Context(), "tzkt."+method,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(requestAttributes(req, method)...),
+			)
+			defer span.End()
+
+			req = req.WithContext(ctx)
+			c.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next.Do(req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return nil, err
+			}
+
+			span.SetAttributes(StatusCodeKey.Int(resp.StatusCode))
+			if resp.StatusCode >= 400 {
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+				return resp, nil
+			}
+
+			// streamed responses are decoded as they are read and can not be counted
+			if tzkt.Streamed(req.Context()) {
+				return resp, nil
+			}
+
+			count, err := countResults(resp)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return nil, err
+			}
+			if count >= 0 {
+				span.SetAttributes(ResultCountKey.Int(count))
+			}
+
+			return resp, nil
+		})
+	}
+}
+
+func requestAttributes(req *http.Request, method string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		MethodKey.String(method),
+		PathKey.String(req.URL.Path),
+		AttemptKey.Int(tzkt.Attempt(req.Context())),
+	}
+
+	for name, values := range req.URL.Query() {
+		attrs = append(attrs, attribute.StringSlice(QueryKeyPrefix+name, values))
+	}
+
+	return attrs
+}
+
+// countResults returns the number of items of a response listing them, or -1
+// for any other response. The body of the response stays readable.
+func countResults(resp *http.Response) (int, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		return -1, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		// decoding errors are the concern of the client
+		return -1, nil
+	}
+
+	return len(items), nil
+}
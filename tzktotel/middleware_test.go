@@ -0,0 +1,112 @@
+package tzktotel_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+	"github.com/bitmark-inc/tzkt-go/tzktotel"
+	"github.com/bitmark-inc/tzkt-go/tzkttest"
+)
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func TestMiddleware(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+
+	srv.AddTokenTransfers(
+		map[string]interface{}{"id": 1, "level": 10, "token": map[string]interface{}{"contract": map[string]interface{}{"address": "KT1"}, "tokenId": "1", "standard": "fa2"}},
+		map[string]interface{}{"id": 2, "level": 11, "token": map[string]interface{}{"contract": map[string]interface{}{"address": "KT1"}, "tokenId": "1", "standard": "fa2"}},
+	)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	c := tzkt.New("",
+		tzkt.WithBaseURL(srv.URL),
+		tzkt.WithRetryPolicy(tzkt.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		tzkt.WithMiddleware(tzktotel.Middleware(
+			tzktotel.WithTracerProvider(provider),
+			tzktotel.WithPropagators(propagation.TraceContext{}),
+		)),
+	)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	srv.FailNext(1, http.StatusServiceUnavailable)
+	transfers, err := c.GetTokenTransfersWithContext(ctx, "KT1", "1", 10)
+	parent.End()
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 2)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+
+	failed, succeeded := spans[0], spans[1]
+	for _, span := range []sdktrace.ReadOnlySpan{failed, succeeded} {
+		assert.Equal(t, "tzkt.GetTokenTransfers", span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+
+	attrs := attributes(failed)
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, int64(503), attrs[tzktotel.StatusCodeKey].AsInt64())
+	assert.Equal(t, int64(1), attrs[tzktotel.AttemptKey].AsInt64())
+
+	attrs = attributes(succeeded)
+	assert.Equal(t, "GetTokenTransfers", attrs[tzktotel.MethodKey].AsString())
+	assert.Equal(t, "/v1/tokens/transfers", attrs[tzktotel.PathKey].AsString())
+	assert.Equal(t, []string{"KT1"}, attrs[tzktotel.QueryKeyPrefix+"token.contract"].AsStringSlice())
+	assert.Equal(t, int64(200), attrs[tzktotel.StatusCodeKey].AsInt64())
+	assert.Equal(t, int64(2), attrs[tzktotel.ResultCountKey].AsInt64())
+	assert.Equal(t, int64(2), attrs[tzktotel.AttemptKey].AsInt64())
+}
+
+func TestMiddlewarePropagation(t *testing.T) {
+	var traceparent string
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	capture := func(next tzkt.Doer) tzkt.Doer {
+		return tzkt.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			traceparent = req.Header.Get("Traceparent")
+			return next.Do(req)
+		})
+	}
+
+	c := tzkt.New("",
+		tzkt.WithBaseURL(srv.URL),
+		tzkt.WithMiddleware(tzktotel.Middleware(
+			tzktotel.WithTracerProvider(provider),
+			tzktotel.WithPropagators(propagation.TraceContext{}),
+		), capture),
+	)
+
+	_, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Contains(t, traceparent, spans[0].SpanContext().SpanID().String())
+	_, ok := attributes(spans[0])[tzktotel.ResultCountKey]
+	assert.False(t, ok)
+}
@@ -0,0 +1,100 @@
+// Package tzktprom exports the metrics of a tzkt client to Prometheus.
+//
+//	collector := tzktprom.NewCollector("")
+//	prometheus.MustRegister(collector)
+//	client := tzkt.New("mainnet", tzkt.WithMetrics(collector))
+package tzktprom
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+)
+
+// DefaultNamespace prefixes the metric names when no namespace is given
+const DefaultNamespace = "tzkt"
+
+// Collector is a tzkt.Metrics which is also a prometheus.Collector. Every
+// metric is labelled by the client method making the api calls.
+type Collector struct {
+	requests       *prometheus.CounterVec
+	duration       *prometheus.HistogramVec
+	tooMany        *prometheus.CounterVec
+	retries        *prometheus.CounterVec
+	decodeFailures *prometheus.CounterVec
+}
+
+var _ tzkt.Metrics = (*Collector)(nil)
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector returns a Collector whose metric names are prefixed by namespace
+func NewCollector(namespace string) *Collector {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Number of api requests, by client method and status code.",
+		}, []string{"method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the api requests, by client method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		tooMany: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "too_many_requests_total",
+			Help:      "Number of api requests rejected with 429, by client method.",
+		}, []string{"method"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Number of retried api requests, by client method.",
+		}, []string{"method"}),
+		decodeFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "decode_failures_total",
+			Help:      "Number of api responses which could not be decoded, by client method.",
+		}, []string{"method"}),
+	}
+}
+
+func (c *Collector) ObserveRequest(method string, statusCode int, duration time.Duration) {
+	c.requests.WithLabelValues(method, strconv.Itoa(statusCode)).Inc()
+	c.duration.WithLabelValues(method).Observe(duration.Seconds())
+
+	if statusCode == 429 {
+		c.tooMany.WithLabelValues(method).Inc()
+	}
+}
+
+func (c *Collector) ObserveRetry(method string) {
+	c.retries.WithLabelValues(method).Inc()
+}
+
+func (c *Collector) ObserveDecodeFailure(method string) {
+	c.decodeFailures.WithLabelValues(method).Inc()
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.tooMany.Describe(ch)
+	c.retries.Describe(ch)
+	c.decodeFailures.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.tooMany.Collect(ch)
+	c.retries.Collect(ch)
+	c.decodeFailures.Collect(ch)
+}
@@ -0,0 +1,76 @@
+package tzktprom_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+	"github.com/bitmark-inc/tzkt-go/tzktprom"
+	"github.com/bitmark-inc/tzkt-go/tzkttest"
+)
+
+func TestCollector(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+
+	collector := tzktprom.NewCollector("")
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	c := tzkt.New("",
+		tzkt.WithBaseURL(srv.URL),
+		tzkt.WithRetryPolicy(tzkt.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		tzkt.WithMetrics(collector),
+	)
+
+	srv.FailNext(1, http.StatusTooManyRequests)
+	_, err := c.GetTokenTransfersCount("KT1", "1")
+	assert.NoError(t, err)
+
+	_, err = c.GetTokenTransfers("KT1", "1", 10)
+	assert.NoError(t, err)
+
+	expected := `
+# HELP tzkt_requests_total Number of api requests, by client method and status code.
+# TYPE tzkt_requests_total counter
+tzkt_requests_total{code="200",method="GetTokenTransfers"} 1
+tzkt_requests_total{code="200",method="GetTokenTransfersCount"} 1
+tzkt_requests_total{code="429",method="GetTokenTransfersCount"} 1
+# HELP tzkt_retries_total Number of retried api requests, by client method.
+# TYPE tzkt_retries_total counter
+tzkt_retries_total{method="GetTokenTransfersCount"} 1
+# HELP tzkt_too_many_requests_total Number of api requests rejected with 429, by client method.
+# TYPE tzkt_too_many_requests_total counter
+tzkt_too_many_requests_total{method="GetTokenTransfersCount"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"tzkt_requests_total", "tzkt_retries_total", "tzkt_too_many_requests_total"))
+	assert.Equal(t, 2, testutil.CollectAndCount(collector, "tzkt_request_duration_seconds"))
+}
+
+func TestCollectorDecodeFailure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"not":"a level"}`))
+	}))
+	defer ts.Close()
+
+	collector := tzktprom.NewCollector("indexer")
+	c := tzkt.New("", tzkt.WithBaseURL(ts.URL), tzkt.WithMetrics(collector))
+
+	_, err := c.GetLevelByTime(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC))
+	assert.Error(t, err)
+
+	expected := `
+# HELP indexer_decode_failures_total Number of api responses which could not be decoded, by client method.
+# TYPE indexer_decode_failures_total counter
+indexer_decode_failures_total{method="GetLevelByTime"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "indexer_decode_failures_total"))
+}
@@ -0,0 +1,306 @@
+package tzkttest
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reserved are the query parameters which are not filters
+var reserved = map[string]bool{
+	"limit":         true,
+	"offset":        true,
+	"offset.cr":     true,
+	"offset.pg":     true,
+	"sort":          true,
+	"sort.asc":      true,
+	"sort.desc":     true,
+	"select":        true,
+	"select.values": true,
+}
+
+var modes = map[string]bool{
+	"eq": true, "ne": true, "gt": true, "ge": true, "lt": true, "le": true,
+	"in": true, "ni": true, "as": true, "un": true, "null": true, "any": true, "all": true,
+}
+
+// queryItems filters, sorts, pages and projects items the way the TzKT API does
+func queryItems(items []Item, query map[string][]string) ([]interface{}, error) {
+	filtered, err := filterItems(items, query)
+	if err != nil {
+		return nil, err
+	}
+
+	sortField, desc := "id", false
+	if v := first(query, "sort.desc"); v != "" {
+		sortField, desc = v, true
+	} else if v := first(query, "sort.asc"); v != "" {
+		sortField = v
+	} else if v := first(query, "sort"); v != "" {
+		sortField = v
+	}
+	sortItems(filtered, sortField, desc)
+
+	limit := 100
+	if v := first(query, "limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 || limit > 10000 {
+			return nil, fmt.Errorf("invalid limit: %s", v)
+		}
+	}
+
+	if v := first(query, "offset.cr"); v != "" {
+		if sortField != "id" {
+			return nil, fmt.Errorf("offset.cr requires sorting by id")
+		}
+
+		cursor := 1
+		if desc {
+			cursor = -1
+		}
+
+		var rest []Item
+		for _, item := range filtered {
+			if compare(lookup(item, "id"), v)*cursor > 0 {
+				rest = append(rest, item)
+			}
+		}
+		filtered = rest
+	} else {
+		skip := 0
+		if v := first(query, "offset"); v != "" {
+			if skip, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("invalid offset: %s", v)
+			}
+		} else if v := first(query, "offset.pg"); v != "" {
+			page, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid offset.pg: %s", v)
+			}
+			skip = page * limit
+		}
+
+		if skip > len(filtered) {
+			skip = len(filtered)
+		}
+		filtered = filtered[skip:]
+	}
+
+	if len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+
+	return project(filtered, query), nil
+}
+
+// filterItems returns the items matching every filter of the query
+func filterItems(items []Item, query map[string][]string) ([]Item, error) {
+	result := []Item{}
+
+	for _, item := range items {
+		ok, err := matchItem(item, query)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func matchItem(item Item, query map[string][]string) (bool, error) {
+	for key, values := range query {
+		if reserved[key] || len(values) == 0 {
+			continue
+		}
+
+		field, mode := key, "eq"
+		if i := strings.LastIndex(key, "."); i > 0 && modes[key[i+1:]] {
+			field, mode = key[:i], key[i+1:]
+		}
+
+		ok, err := match(lookup(item, field), mode, values[0])
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func match(actual interface{}, mode, value string) (bool, error) {
+	switch mode {
+	case "eq":
+		return actual != nil && compare(actual, value) == 0, nil
+	case "ne":
+		return actual == nil || compare(actual, value) != 0, nil
+	case "gt":
+		return actual != nil && compare(actual, value) > 0, nil
+	case "ge":
+		return actual != nil && compare(actual, value) >= 0, nil
+	case "lt":
+		return actual != nil && compare(actual, value) < 0, nil
+	case "le":
+		return actual != nil && compare(actual, value) <= 0, nil
+	case "in", "ni":
+		found := false
+		for _, v := range strings.Split(value, ",") {
+			if actual != nil && compare(actual, v) == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (mode == "in"), nil
+	case "as", "un":
+		re, err := regexp.Compile("^" + strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*") + "$")
+		if err != nil {
+			return false, err
+		}
+		return actual != nil && re.MatchString(toString(actual)) == (mode == "as"), nil
+	case "null":
+		isNull := value == "" || value == "true"
+		return (actual == nil) == isNull, nil
+	case "any", "all":
+		list, _ := actual.([]interface{})
+		count := 0
+		wanted := strings.Split(value, ",")
+		for _, w := range wanted {
+			for _, a := range list {
+				if compare(a, w) == 0 {
+					count++
+					break
+				}
+			}
+		}
+		if mode == "any" {
+			return count > 0, nil
+		}
+		return count == len(wanted), nil
+	}
+
+	return false, fmt.Errorf("unsupported filter mode: %s", mode)
+}
+
+// lookup returns the value at a dotted path of an item
+func lookup(item Item, path string) interface{} {
+	var v interface{} = map[string]interface{}(item)
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+
+	return v
+}
+
+// project applies the select and select.values parameters
+func project(items []Item, query map[string][]string) []interface{} {
+	selection, values := first(query, "select"), false
+	if v := first(query, "select.values"); v != "" {
+		selection, values = v, true
+	}
+
+	result := make([]interface{}, 0, len(items))
+
+	if selection == "" {
+		for _, item := range items {
+			result = append(result, item)
+		}
+		return result
+	}
+
+	type column struct{ path, name string }
+	var columns []column
+	for _, s := range strings.Split(selection, ",") {
+		parts := strings.SplitN(strings.TrimSpace(s), " as ", 2)
+		c := column{path: strings.TrimSpace(parts[0]), name: strings.TrimSpace(parts[0])}
+		if len(parts) == 2 {
+			c.name = strings.TrimSpace(parts[1])
+		}
+		columns = append(columns, c)
+	}
+
+	for _, item := range items {
+		switch {
+		case len(columns) == 1:
+			result = append(result, lookup(item, columns[0].path))
+		case values:
+			row := make([]interface{}, 0, len(columns))
+			for _, c := range columns {
+				row = append(row, lookup(item, c.path))
+			}
+			result = append(result, row)
+		default:
+			row := map[string]interface{}{}
+			for _, c := range columns {
+				row[c.name] = lookup(item, c.path)
+			}
+			result = append(result, row)
+		}
+	}
+
+	return result
+}
+
+// compare compares a fixture value to another value as numbers, times or
+// strings, whichever fits both. Accounts are compared by address.
+func compare(a, b interface{}) int {
+	x, y := toString(a), toString(b)
+
+	if fx, ok := new(big.Float).SetString(x); ok {
+		if fy, ok := new(big.Float).SetString(y); ok {
+			return fx.Cmp(fy)
+		}
+	}
+
+	if tx, ok := parseTime(x); ok {
+		if ty, ok := parseTime(y); ok {
+			switch {
+			case tx.Before(ty):
+				return -1
+			case tx.After(ty):
+				return 1
+			}
+			return 0
+		}
+	}
+
+	return strings.Compare(x, y)
+}
+
+func toString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case map[string]interface{}:
+		return toString(t["address"])
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func parseTime(v interface{}) (time.Time, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	return t, err == nil
+}
+
+func first(query map[string][]string, key string) string {
+	if v := query[key]; len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,211 @@
+package tzkttest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Mode selects how a Recorder handles requests
+type Mode int
+
+const (
+	// ModeReplay serves responses from the fixtures only and fails on a missing fixture
+	ModeReplay Mode = iota
+	// ModeRecord sends every request to the network and saves the responses
+	ModeRecord
+	// ModeReplayOrRecord serves existing fixtures and records the missing ones
+	ModeReplayOrRecord
+)
+
+// RecordModeFromEnv returns ModeRecord when the TZKT_RECORD environment
+// variable is set to 1 or true, and ModeReplay otherwise
+func RecordModeFromEnv() Mode {
+	switch strings.ToLower(os.Getenv("TZKT_RECORD")) {
+	case "1", "true":
+		return ModeRecord
+	}
+
+	return ModeReplay
+}
+
+// fixture is the file format of a recorded response. The body is kept as
+// json when possible so fixtures stay readable and diffable.
+type fixture struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Text   string          `json:"text,omitempty"`
+}
+
+// Recorder is an http.RoundTripper which records real TzKT responses to a
+// directory of fixtures and replays them afterwards. Requests are keyed by
+// method and normalized url, so the order of query parameters does not matter.
+//
+//	rec := tzkttest.NewRecorder("testdata/fixtures", tzkttest.RecordModeFromEnv(), nil)
+//	c := tzkt.New("", tzkt.WithHTTPClient(&http.Client{Transport: rec}))
+type Recorder struct {
+	dir  string
+	mode Mode
+	next http.RoundTripper
+}
+
+// NewRecorder returns a Recorder storing its fixtures in dir. Requests are
+// sent through next when recording, or http.DefaultTransport when next is nil.
+func NewRecorder(dir string, mode Mode, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Recorder{
+		dir:  dir,
+		mode: mode,
+		next: next,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := NormalizeRequest(req)
+	path := r.fixturePath(key)
+
+	if r.mode != ModeRecord {
+		f, err := readFixture(path)
+		switch {
+		case err == nil:
+			return f.response(req), nil
+		case !os.IsNotExist(err):
+			return nil, err
+		case r.mode == ModeReplay:
+			return nil, fmt.Errorf("tzkttest: no fixture for %s at %s, record it with TZKT_RECORD=1", key, path)
+		}
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	f := fixture{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: http.Header{},
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		f.Header.Set("Content-Type", ct)
+	}
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		f.Header.Set("Retry-After", ra)
+	}
+	if json.Valid(body) {
+		f.Body = body
+	} else {
+		f.Text = string(body)
+	}
+
+	if err := writeFixture(path, f); err != nil {
+		return nil, err
+	}
+
+	return f.response(req), nil
+}
+
+// NormalizeRequest returns the key of a request: its method and url with
+// the query parameters sorted
+func NormalizeRequest(req *http.Request) string {
+	u := *req.URL
+	u.RawQuery = u.Query().Encode()
+	u.Fragment = ""
+
+	return req.Method + " " + u.String()
+}
+
+// fixturePath returns the file of a request key. Files are grouped by host
+// and named after a hash of the key since urls are too long for file names.
+func (r *Recorder) fixturePath(key string) string {
+	host := "unknown"
+	if i := strings.Index(key, " "); i >= 0 {
+		if u, err := url.Parse(key[i+1:]); err == nil && u.Host != "" {
+			host = strings.ReplaceAll(u.Host, ":", "_")
+		}
+	}
+
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(r.dir, host, hex.EncodeToString(sum[:8])+".json")
+}
+
+func readFixture(path string) (fixture, error) {
+	var f fixture
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return f, err
+	}
+
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("tzkttest: invalid fixture %s: %w", path, err)
+	}
+
+	return f, nil
+}
+
+func writeFixture(path string, f fixture) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func (f fixture) response(req *http.Request) *http.Response {
+	// bodies are indented in the fixture files while the api sends compact json
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, f.Body); err != nil {
+		buf.Write(f.Body)
+	}
+
+	body := buf.Bytes()
+	if f.Text != "" {
+		body = []byte(f.Text)
+	}
+
+	header := f.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
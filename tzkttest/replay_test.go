@@ -0,0 +1,51 @@
+package tzkttest_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+	"github.com/bitmark-inc/tzkt-go/tzkttest"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := tzkttest.NewServer()
+	srv.AddTokens(`{"id":1,"contract":{"address":"KT1a"},"tokenId":"7","metadata":{"formats":"[{\"uri\":\"ipfs://a\",\"mimeType\":\"image/png\"}]"}}`)
+
+	dir := t.TempDir()
+
+	rec := tzkttest.NewRecorder(dir, tzkttest.ModeRecord, nil)
+	c := tzkt.New("", tzkt.WithBaseURL(srv.URL), tzkt.WithHTTPClient(&http.Client{Transport: rec}))
+
+	token, err := c.GetContractToken("KT1a", "7")
+	assert.NoError(t, err)
+	assert.Len(t, token.Metadata.Formats, 1)
+
+	_, err = c.GetContractToken("KT1a", "8")
+	assert.ErrorIs(t, err, tzkt.ErrNotFound)
+
+	// replay the recorded responses once the server is gone
+	srv.Close()
+
+	rec = tzkttest.NewRecorder(dir, tzkttest.ModeReplay, nil)
+	c = tzkt.New("", tzkt.WithBaseURL(srv.URL), tzkt.WithHTTPClient(&http.Client{Transport: rec}))
+
+	token, err = c.GetContractToken("KT1a", "7")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", string(token.Metadata.Formats[0].MIMEType))
+
+	_, err = c.GetContractToken("KT1a", "8")
+	assert.ErrorIs(t, err, tzkt.ErrNotFound)
+
+	_, err = c.GetContractToken("KT1a", "9")
+	assert.ErrorContains(t, err, "no fixture for GET")
+}
+
+func TestNormalizeRequest(t *testing.T) {
+	a, _ := http.NewRequest("GET", "https://api.tzkt.io/v1/tokens?tokenId=1&contract=KT1a", nil)
+	b, _ := http.NewRequest("GET", "https://api.tzkt.io/v1/tokens?contract=KT1a&tokenId=1", nil)
+
+	assert.Equal(t, tzkttest.NormalizeRequest(a), tzkttest.NormalizeRequest(b))
+}
@@ -0,0 +1,589 @@
+// Package tzkttest provides a fake TzKT API for tests which do not want to
+// depend on the public api.
+//
+//	srv := tzkttest.NewServer()
+//	defer srv.Close()
+//
+//	srv.AddTokens(`{"id":1,"contract":{"address":"KT1..."},"tokenId":"0"}`)
+//	c := tzkt.New("", tzkt.WithBaseURL(srv.URL))
+//
+// Fixtures are json objects shaped like the api responses. The list routes
+// support the filter modes, sort, select, limit and offset parameters used by
+// the client.
+package tzkttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Item is a fixture decoded as a generic json object
+type Item map[string]interface{}
+
+// collections are the list routes served by the fake server
+const (
+	tokens         = "/v1/tokens"
+	tokenBalances  = "/v1/tokens/balances"
+	tokenTransfers = "/v1/tokens/transfers"
+	transactions   = "/v1/operations/transactions"
+	bigmaps        = "/v1/bigmaps"
+	bigmapUpdates  = "/v1/bigmaps/updates"
+	blocks         = "/v1/blocks"
+	protocols      = "/v1/protocols"
+	accounts       = "/v1/accounts"
+)
+
+type failure struct {
+	status     int
+	retryAfter string
+}
+
+// Server is a fake TzKT API backed by in-memory fixtures
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	collections map[string][]Item
+	bigmapKeys  map[int][]Item
+	accountOps  map[string][]Item
+	balanceHist map[string][]Item
+	tokenHist   []Item
+	failures    []failure
+	requests    int
+	unsynced    bool
+}
+
+// NewServer starts a fake TzKT API. It should be closed when the test ends.
+func NewServer() *Server {
+	s := &Server{
+		collections: map[string][]Item{},
+		bigmapKeys:  map[int][]Item{},
+		accountOps:  map[string][]Item{},
+		balanceHist: map[string][]Item{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Add adds fixtures to a list route, e.g. "/v1/tokens". A fixture is either a
+// json document as a string or []byte, or any value encodable to a json object.
+func (s *Server) Add(path string, items ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.collections[path] = append(s.collections[path], toItems(items)...)
+}
+
+// AddTokens adds fixtures served by /v1/tokens
+func (s *Server) AddTokens(items ...interface{}) {
+	s.Add(tokens, items...)
+}
+
+// AddTokenBalances adds fixtures served by /v1/tokens/balances
+func (s *Server) AddTokenBalances(items ...interface{}) {
+	s.Add(tokenBalances, items...)
+}
+
+// AddTokenTransfers adds fixtures served by /v1/tokens/transfers
+func (s *Server) AddTokenTransfers(items ...interface{}) {
+	s.Add(tokenTransfers, items...)
+}
+
+// AddTransactions adds fixtures served by /v1/operations/transactions
+func (s *Server) AddTransactions(items ...interface{}) {
+	s.Add(transactions, items...)
+}
+
+// AddBigmaps adds fixtures served by /v1/bigmaps
+func (s *Server) AddBigmaps(items ...interface{}) {
+	s.Add(bigmaps, items...)
+}
+
+// AddBigmapUpdates adds fixtures served by /v1/bigmaps/updates
+func (s *Server) AddBigmapUpdates(items ...interface{}) {
+	s.Add(bigmapUpdates, items...)
+}
+
+// AddBigmapKeys adds fixtures served by /v1/bigmaps/{pointer}/keys
+func (s *Server) AddBigmapKeys(pointer int, items ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bigmapKeys[pointer] = append(s.bigmapKeys[pointer], toItems(items)...)
+}
+
+// AddBlocks adds fixtures served by /v1/blocks, /v1/blocks/{level} and
+// /v1/head. The operations of a fixture, e.g. its "transactions", are only
+// served by /v1/blocks/{level} with operations=true.
+func (s *Server) AddBlocks(items ...interface{}) {
+	s.Add(blocks, items...)
+}
+
+// AddProtocols adds fixtures served by /v1/protocols and /v1/protocols/{code}
+func (s *Server) AddProtocols(items ...interface{}) {
+	s.Add(protocols, items...)
+}
+
+// AddAccounts adds fixtures served by /v1/accounts and /v1/accounts/{address}
+func (s *Server) AddAccounts(items ...interface{}) {
+	s.Add(accounts, items...)
+}
+
+// AddAccountOperations adds fixtures served by /v1/accounts/{address}/operations
+func (s *Server) AddAccountOperations(address string, items ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accountOps[address] = append(s.accountOps[address], toItems(items)...)
+}
+
+// AddAccountBalanceHistory adds fixtures served by
+// /v1/accounts/{address}/balance_history/{level}. A fixture is the balance of
+// the account from a level onwards, e.g. {"level":100,"balance":2500000}.
+func (s *Server) AddAccountBalanceHistory(address string, items ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.balanceHist[address] = append(s.balanceHist[address], toItems(items)...)
+}
+
+// AddHistoricalTokenBalances adds fixtures served by
+// /v1/tokens/historical_balances/{level}. A fixture is a token balance like
+// the ones of /v1/tokens/balances whose lastLevel is the level it was set at.
+func (s *Server) AddHistoricalTokenBalances(items ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokenHist = append(s.tokenHist, toItems(items)...)
+}
+
+// FailNext makes the next n requests fail with the given status code.
+// A 429 comes with a Retry-After header of zero seconds.
+func (s *Server) FailNext(n int, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		f := failure{status: status}
+		if status == http.StatusTooManyRequests {
+			f.retryAfter = "0"
+		}
+		s.failures = append(s.failures, f)
+	}
+}
+
+// SetSynced sets whether /v1/head reports the indexer as synced with the
+// chain, which it does by default
+func (s *Server) SetSynced(synced bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unsynced = !synced
+}
+
+// Requests returns the number of requests received so far
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+func toItems(values []interface{}) []Item {
+	items := make([]Item, 0, len(values))
+	for _, v := range values {
+		var data []byte
+		switch d := v.(type) {
+		case string:
+			data = []byte(d)
+		case []byte:
+			data = d
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				panic(fmt.Sprintf("tzkttest: invalid fixture: %s", err))
+			}
+			data = b
+		}
+
+		var item Item
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		if err := d.Decode(&item); err != nil {
+			panic(fmt.Sprintf("tzkttest: invalid fixture: %s", err))
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+
+	if len(s.failures) > 0 {
+		f := s.failures[0]
+		s.failures = s.failures[1:]
+
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		writeError(w, f.status, "injected failure")
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	query := r.URL.Query()
+
+	if items, ok := s.collections[path]; ok || isCollection(path) {
+		if path == blocks {
+			items = withoutOperations(items)
+		}
+		s.serveList(w, items, query)
+		return
+	}
+
+	if path == "/v1/head" {
+		s.serveHead(w)
+		return
+	}
+
+	if strings.HasSuffix(path, "/count") {
+		if items, ok := s.collections[strings.TrimSuffix(path, "/count")]; ok || isCollection(strings.TrimSuffix(path, "/count")) {
+			items, err := filterItems(items, query)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSON(w, len(items))
+			return
+		}
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path, "/v1/"), "/")
+	switch {
+	case len(segments) == 3 && segments[0] == "bigmaps" && segments[2] == "keys":
+		pointer, err := strconv.Atoi(segments[1])
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid bigmap pointer")
+			return
+		}
+		s.serveList(w, s.bigmapKeys[pointer], query)
+	case len(segments) == 3 && segments[0] == "operations" && segments[1] == "transactions":
+		s.serveTransactions(w, segments[2])
+	case len(segments) == 4 && segments[0] == "operations" && segments[1] == "transactions" && segments[3] == "status":
+		s.serveTransactionStatus(w, segments[2])
+	case len(segments) == 2 && segments[0] == "protocols":
+		s.serveProtocol(w, segments[1])
+	case len(segments) == 2 && segments[0] == "accounts":
+		s.serveAccount(w, segments[1])
+	case len(segments) == 4 && segments[0] == "accounts" && segments[2] == "balance_history":
+		s.serveBalanceHistory(w, segments[1], segments[3])
+	case len(segments) == 3 && segments[0] == "tokens" && segments[1] == "historical_balances":
+		s.serveHistoricalTokenBalances(w, segments[2], query)
+	case len(segments) == 3 && segments[0] == "accounts" && segments[2] == "operations":
+		s.serveAccountOperations(w, segments[1], query)
+	case len(segments) == 2 && segments[0] == "blocks":
+		s.serveBlock(w, segments[1], first(query, "operations") == "true")
+	case len(segments) == 3 && segments[0] == "blocks" && segments[2] == "level":
+		s.serveLevelByTime(w, segments[1])
+	case len(segments) == 3 && segments[0] == "blocks" && segments[2] == "timestamp":
+		s.serveTimeByLevel(w, segments[1])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func isCollection(path string) bool {
+	switch path {
+	case tokens, tokenBalances, tokenTransfers, transactions, bigmaps, bigmapUpdates, blocks, protocols, accounts:
+		return true
+	}
+
+	return false
+}
+
+func (s *Server) serveList(w http.ResponseWriter, items []Item, query map[string][]string) {
+	result, err := queryItems(items, query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, result)
+}
+
+func (s *Server) serveTransactions(w http.ResponseWriter, hash string) {
+	result := []Item{}
+	for _, tx := range s.collections[transactions] {
+		if tx["hash"] == hash {
+			result = append(result, tx)
+		}
+	}
+
+	writeJSON(w, result)
+}
+
+func (s *Server) serveTransactionStatus(w http.ResponseWriter, hash string) {
+	for _, tx := range s.collections[transactions] {
+		if tx["hash"] != hash {
+			continue
+		}
+
+		switch tx["status"] {
+		case "applied":
+			writeJSON(w, true)
+		case nil:
+			writeJSON(w, nil)
+		default:
+			writeJSON(w, false)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) serveProtocol(w http.ResponseWriter, code string) {
+	for _, p := range s.collections[protocols] {
+		if compare(p["code"], code) == 0 {
+			writeJSON(w, p)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) serveAccount(w http.ResponseWriter, address string) {
+	for _, a := range s.collections[accounts] {
+		if a["address"] == address {
+			writeJSON(w, a)
+			return
+		}
+	}
+
+	// like tzkt, addresses without fixture are accounts which never received anything
+	writeJSON(w, Item{"type": "empty", "address": address, "counter": 0})
+}
+
+// serveAccountOperations translates the parameters specific to the operations
+// of an account into the usual ones: type is a list, entrypoint the one of the
+// parameter, lastId a cursor and sort is 0 for ascending or 1 (the default)
+// for descending ids
+func (s *Server) serveAccountOperations(w http.ResponseWriter, address string, query map[string][]string) {
+	q := map[string][]string{}
+	for k, v := range query {
+		q[k] = v
+	}
+	delete(q, "sort")
+	delete(q, "lastId")
+	delete(q, "type")
+	delete(q, "entrypoint")
+
+	if v := first(query, "type"); v != "" {
+		q["type.in"] = []string{v}
+	}
+	if v := first(query, "entrypoint"); v != "" {
+		q["parameter.entrypoint"] = []string{v}
+	}
+
+	if first(query, "sort") == "0" {
+		q["sort.asc"] = []string{"id"}
+	} else {
+		q["sort.desc"] = []string{"id"}
+	}
+
+	if v := first(query, "lastId"); v != "" {
+		q["offset.cr"] = []string{v}
+	}
+
+	s.serveList(w, s.accountOps[address], q)
+}
+
+func (s *Server) serveBalanceHistory(w http.ResponseWriter, address, level string) {
+	if _, err := strconv.ParseUint(level, 10, 64); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid level")
+		return
+	}
+
+	var balance interface{} = 0
+	var latest interface{}
+	for _, b := range s.balanceHist[address] {
+		if compare(b["level"], level) > 0 || (latest != nil && compare(b["level"], latest) < 0) {
+			continue
+		}
+		latest = b["level"]
+		balance = b["balance"]
+	}
+
+	writeJSON(w, balance)
+}
+
+func (s *Server) serveHistoricalTokenBalances(w http.ResponseWriter, level string, query map[string][]string) {
+	if _, err := strconv.ParseUint(level, 10, 64); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid level")
+		return
+	}
+
+	latest := map[string]Item{}
+	for _, b := range s.tokenHist {
+		if compare(b["lastLevel"], level) > 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%s", toString(lookup(b, "account")), toString(lookup(b, "token.contract")), toString(lookup(b, "token.tokenId")))
+		if l, ok := latest[key]; !ok || compare(b["lastLevel"], l["lastLevel"]) >= 0 {
+			latest[key] = b
+		}
+	}
+
+	var items []Item
+	for _, b := range latest {
+		if compare(b["balance"], "0") != 0 {
+			items = append(items, b)
+		}
+	}
+
+	s.serveList(w, items, query)
+}
+
+func withoutOperations(items []Item) []Item {
+	result := make([]Item, 0, len(items))
+	for _, item := range items {
+		b := Item{}
+		for k, v := range item {
+			// the lists of a block are its operations, e.g. "endorsements"
+			if _, ok := v.([]interface{}); !ok {
+				b[k] = v
+			}
+		}
+		result = append(result, b)
+	}
+
+	return result
+}
+
+func (s *Server) serveHead(w http.ResponseWriter) {
+	head := Item{"level": 0, "knownLevel": 0, "synced": !s.unsynced}
+	for _, b := range s.collections[blocks] {
+		if compare(b["level"], head["level"]) >= 0 {
+			head = Item{
+				"cycle":      b["cycle"],
+				"level":      b["level"],
+				"hash":       b["hash"],
+				"timestamp":  b["timestamp"],
+				"knownLevel": b["level"],
+				"synced":     !s.unsynced,
+			}
+		}
+	}
+
+	writeJSON(w, head)
+}
+
+func (s *Server) serveBlock(w http.ResponseWriter, levelOrHash string, operations bool) {
+	_, err := strconv.ParseUint(levelOrHash, 10, 64)
+	field := "level"
+	if err != nil {
+		field = "hash"
+	}
+
+	for _, b := range s.collections[blocks] {
+		if compare(b[field], levelOrHash) != 0 {
+			continue
+		}
+
+		if !operations {
+			b = withoutOperations([]Item{b})[0]
+		}
+		writeJSON(w, b)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) serveLevelByTime(w http.ResponseWriter, timestamp string) {
+	at, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid timestamp")
+		return
+	}
+
+	var level interface{}
+	var latest time.Time
+	for _, b := range s.collections[blocks] {
+		t, ok := parseTime(b["timestamp"])
+		if !ok || t.After(at) || t.Before(latest) {
+			continue
+		}
+		latest = t
+		level = b["level"]
+	}
+
+	if level == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(w, level)
+}
+
+func (s *Server) serveTimeByLevel(w http.ResponseWriter, level string) {
+	if _, err := strconv.ParseUint(level, 10, 64); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid level")
+		return
+	}
+
+	for _, b := range s.collections[blocks] {
+		if compare(b["level"], level) == 0 {
+			writeJSON(w, b["timestamp"])
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    status,
+		"message": message,
+	})
+}
+
+// sortItems sorts items in place by the value of field
+func sortItems(items []Item, field string, desc bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		c := compare(lookup(items[i], field), lookup(items[j], field))
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
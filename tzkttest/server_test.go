@@ -0,0 +1,138 @@
+package tzkttest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	tzkt "github.com/bitmark-inc/tzkt-go"
+	"github.com/bitmark-inc/tzkt-go/tzkttest"
+)
+
+func newClient(srv *tzkttest.Server, options ...tzkt.Option) *tzkt.TZKT {
+	return tzkt.New("", append([]tzkt.Option{tzkt.WithBaseURL(srv.URL)}, options...)...)
+}
+
+func TestGetContractToken(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+
+	srv.AddTokens(
+		`{"id":1,"contract":{"alias":"Versum Items","address":"KT1LjmAdYQCLBjwv4S2oFkEzyHVkomAf5MrW"},"tokenId":"24216","standard":"fa2","totalSupply":"10","firstTime":"2022-03-01T10:00:00Z","lastTime":"2022-03-02T10:00:00Z"}`,
+		`{"id":2,"contract":{"address":"KT1LjmAdYQCLBjwv4S2oFkEzyHVkomAf5MrW"},"tokenId":"24217","standard":"fa2","totalSupply":"1"}`,
+	)
+
+	c := newClient(srv)
+
+	token, err := c.GetContractToken("KT1LjmAdYQCLBjwv4S2oFkEzyHVkomAf5MrW", "24216")
+	assert.NoError(t, err)
+	assert.Equal(t, "Versum Items", token.Contract.Alias)
+	assert.Equal(t, "24216", token.ID.String())
+
+	_, err = c.GetContractToken("KT1LjmAdYQCLBjwv4S2oFkEzyHVkomAf5MrW", "1")
+	assert.ErrorIs(t, err, tzkt.ErrNotFound)
+}
+
+func TestGetTokenOwners(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+
+	srv.AddTokenBalances(
+		map[string]interface{}{"id": 1, "account": map[string]string{"address": "tz1a"}, "token": map[string]interface{}{"contract": map[string]string{"address": "KT1a"}, "tokenId": "1", "standard": "fa2", "totalSupply": "3"}, "balance": "2", "lastLevel": 20, "lastTime": "2023-04-20T07:09:01Z"},
+		map[string]interface{}{"id": 2, "account": map[string]string{"address": "tz1b"}, "token": map[string]interface{}{"contract": map[string]string{"address": "KT1a"}, "tokenId": "1", "standard": "fa2", "totalSupply": "3"}, "balance": "1", "lastLevel": 10, "lastTime": "2023-04-19T07:09:01Z"},
+		map[string]interface{}{"id": 3, "account": map[string]string{"address": "tz1c"}, "token": map[string]interface{}{"contract": map[string]string{"address": "KT1a"}, "tokenId": "1", "standard": "fa2", "totalSupply": "3"}, "balance": "0", "lastLevel": 5, "lastTime": "2023-04-18T07:09:01Z"},
+	)
+
+	c := newClient(srv)
+
+	owners, err := c.GetTokenOwners("KT1a", "1", 50, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, owners, 2)
+	assert.Equal(t, "tz1b", owners[0].Address)
+	assert.Equal(t, int64(3), owners[0].TotalSupply)
+	assert.Equal(t, "tz1a", owners[1].Address)
+
+	owners, err = c.GetTokenOwners("KT1a", "1", 50, time.Date(2023, 4, 20, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, owners, 1)
+
+	balance, err := c.GetTokenBalanceOfOwner("KT1a", "1", "tz1a")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), balance)
+}
+
+func TestIterateTokenTransfers(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+
+	for i := 1; i <= 25; i++ {
+		srv.AddTokenTransfers(map[string]interface{}{
+			"id":    i * 10,
+			"level": i,
+			"token": map[string]interface{}{"contract": map[string]string{"address": "KT1a"}, "tokenId": "1", "standard": "fa2"},
+			"to":    map[string]string{"address": "tz1a"},
+		})
+	}
+
+	c := newClient(srv)
+
+	it := c.IterateTokenTransfers("KT1a", "1", 10)
+	count := 0
+	for it.Next(context.Background()) {
+		count++
+		assert.Equal(t, uint64(count*10), it.Item().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 25, count)
+
+	total, err := c.GetTokenTransfersCount("KT1a", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 25, total)
+}
+
+func TestBigmapsAndBlocks(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+
+	srv.AddBigmaps(`{"ptr":149772,"contract":{"address":"KT1a"},"path":"token_metadata","tags":["token_metadata"]}`)
+	srv.AddBigmapKeys(149772, `{"id":1,"key":"589146","value":{"token_id":"589146"}}`)
+	srv.AddBlocks(`{"level":10,"timestamp":"2023-09-29T03:17:30Z"}`, `{"level":11,"timestamp":"2023-09-29T03:17:40Z"}`)
+
+	c := newClient(srv)
+
+	p, err := c.GetBigMapPointerForContractTokenMetadata("KT1a")
+	assert.NoError(t, err)
+	assert.Equal(t, 149772, p)
+
+	value, err := c.GetBigMapValueByPointer(p, "589146")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"token_id":"589146"}`, string(value))
+
+	level, err := c.GetLevelByTime(time.Date(2023, 9, 29, 3, 17, 35, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), level)
+}
+
+func TestFailNext(t *testing.T) {
+	srv := tzkttest.NewServer()
+	defer srv.Close()
+
+	srv.AddTransactions(`{"id":1,"hash":"oo1","status":"applied"}`)
+	srv.FailNext(1, http.StatusTooManyRequests)
+	srv.FailNext(1, http.StatusServiceUnavailable)
+
+	c := newClient(srv)
+
+	_, err := c.GetTransactionStatusByTx("oo1")
+	assert.ErrorIs(t, err, tzkt.ErrTooManyRequest)
+
+	c = newClient(srv, tzkt.WithRetryPolicy(tzkt.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+
+	status, err := c.GetTransactionStatusByTx("oo1")
+	assert.NoError(t, err)
+	assert.True(t, *status)
+	assert.Equal(t, 3, srv.Requests())
+}